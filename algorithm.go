@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lazyton/jackpot-verification/verify"
+)
+
+// printAlgorithm describes, step by step, exactly what the verifier computes
+// for a game. Everything printed is read from the game profile and the
+// constants the verification code itself uses, so it cannot drift from the
+// implementation.
+func printAlgorithm(game verify.GameConfig) {
+	hashName := strings.ToUpper(game.HashAlgorithm)
+	fmt.Printf("Provably fair algorithm for game %q\n", game.Name)
+	if game.Description != "" {
+		fmt.Printf("(%s)\n", game.Description)
+	}
+	fmt.Println()
+
+	fmt.Println("1. Server commitment")
+	fmt.Printf("   Before betting opens the server publishes server_hash = hex(%s(server_seed)).\n", hashName)
+	fmt.Printf("   server_hash is %d lowercase hex characters.\n", game.NewHash().Size()*2)
+	fmt.Println()
+
+	fmt.Println("2. Client seed")
+	if game.BetOrder == verify.BetOrderInsertion {
+		fmt.Println("   Bets are taken in the order they were placed, as listed in the round data.")
+	} else {
+		fmt.Println("   Bets are sorted by player_address in ascending byte order; bets by the same")
+		fmt.Println("   player keep their relative order.")
+	}
+	fmt.Println("   Each bet is serialized as player_address + amount + gift_id with no separators,")
+	fmt.Println("   the amount written with exactly three decimals (e.g. 10.500).")
+	if game.ClientSeedMode == verify.ClientSeedHMAC {
+		fmt.Printf("   client_seed = hex(HMAC-SHA256(key=%q, all serialized bets concatenated)).\n", game.ClientSeedKey)
+	} else {
+		fmt.Println("   client_seed = hex(SHA256(all serialized bets concatenated)).")
+	}
+	fmt.Println("   A round anchored to a drand beacon (beacon_round, beacon_value) hashes the")
+	fmt.Println("   beacon value, as lowercase hex, after the bets.")
+	fmt.Println()
+
+	fmt.Println("3. Result")
+	fmt.Printf("   message = %s\n", game.MessageFormat)
+	fmt.Println("   with {round_number} in decimal and the other fields exactly as published.")
+	fmt.Printf("   h = HMAC-%s(key=server_seed, message), read as a big-endian unsigned integer.\n", hashName)
+	rounding := game.ResultRounding
+	if rounding == "" {
+		rounding = verify.RoundingRound
+	}
+	fmt.Printf("   result = (h mod %d) / %g, compared with the claimed result at three decimals.\n", game.Modulus, game.Divisor)
+	fmt.Printf("   Both values are brought to three decimals with %q rounding.\n", rounding)
+	fmt.Printf("   The result therefore lies in [0, %.3f].\n", float64(game.Modulus-1)/game.Divisor)
+	fmt.Println()
+
+	fmt.Println("4. Winner")
+	if game.RangeOrder == verify.BetOrderInsertion {
+		fmt.Println("   Bets are taken in the order listed. Each bet owns a half-open range [start, end)")
+	} else {
+		fmt.Println("   Bets are sorted by player_address. Each bet owns a half-open range [start, end)")
+	}
+	fmt.Println("   of [0, 100) proportional to its amount, start being the running total of the")
+	if game.WinnerRule == verify.WinnerRuleNearest {
+		fmt.Println("   previous bets' shares. The winner is the player whose range midpoint")
+		fmt.Println("   (start + end) / 2 is closest to the result; empty ranges never win and an exact")
+		fmt.Println("   tie goes to the earlier player in sorted order.")
+	} else {
+		fmt.Println("   previous bets' shares. The winner is the player whose range contains the result.")
+	}
+	if game.MinWinningBet > 0 {
+		fmt.Printf("   Bets below %.3f TON are left out of the ranges: they count toward the pot\n", game.MinWinningBet)
+		fmt.Println("   and the client seed but cannot win, and the ranges share out the other bets.")
+	}
+	fmt.Printf("   A result of exactly %g falls in the last non-empty range, which is closed at\n", verify.ResultDomainMax)
+	fmt.Printf("   the top; a result outside [%g, %g] is out of domain unless the round has a single bet.\n", verify.ResultDomainMin, verify.ResultDomainMax)
+	fmt.Println("   A round declaring range_denominator uses it instead of the visible bet total;")
+	fmt.Println("   the uncovered remainder of [0, 100) belongs to undisclosed bets.")
+	if game.TieBreak {
+		fmt.Printf("   A result within %g of a boundary between two players is resolved by\n", verify.TieEpsilon)
+		fmt.Printf("   HMAC-%s(key=server_seed, message + %q): even picks the lower player, odd the upper.\n", hashName, verify.TieBreakSuffix)
+	}
+	if game.WinnerRule != verify.WinnerRuleNearest {
+		fmt.Println("   A result in no range fails verification unless the round declares fallback_rule")
+		fmt.Printf("   %q or %q (the last or first sorted player).\n", verify.FallbackLast, verify.FallbackFirst)
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// archiveSeparator joins an archive path and a member name in source names,
+// e.g. "audit.zip!rounds/1001.json"
+const archiveSeparator = "!"
+
+// isArchive reports whether a path names a supported archive of round files
+func isArchive(name string) bool {
+	name = strings.ToLower(name)
+	for _, ext := range []string{".zip", ".tar", ".tar.gz", ".tgz"} {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// readArchive returns every .json member of a zip or tar(.gz) archive as an
+// in-memory batch source, sorted by member name. Nothing is extracted to disk.
+func readArchive(archive string) ([]batchSource, error) {
+	var sources []batchSource
+	add := func(name string, r io.Reader) error {
+		if !isRoundMember(name) {
+			return nil
+		}
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		sources = append(sources, batchSource{
+			Name: archive + archiveSeparator + name,
+			Data: normalizeInput(data),
+		})
+		return nil
+	}
+
+	var err error
+	if strings.HasSuffix(strings.ToLower(archive), ".zip") {
+		err = readZip(archive, add)
+	} else {
+		err = readTar(archive, add)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(sources, func(i, j int) bool { return sources[i].Name < sources[j].Name })
+	return sources, nil
+}
+
+// isRoundMember skips directories, non-JSON files and the "._" resource
+// forks macOS adds to archives it creates
+func isRoundMember(name string) bool {
+	base := path.Base(name)
+	return strings.HasSuffix(strings.ToLower(base), ".json") &&
+		!strings.HasPrefix(base, "._") &&
+		!strings.HasPrefix(name, "__MACOSX/")
+}
+
+func readZip(archive string, add func(string, io.Reader) error) error {
+	zr, err := zip.OpenReader(archive)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		err = add(f.Name, rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readTar(archive string, add func(string, io.Reader) error) error {
+	file, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var r io.Reader = file
+	if lower := strings.ToLower(archive); strings.HasSuffix(lower, ".gz") || strings.HasSuffix(lower, ".tgz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := add(hdr.Name, tr); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+)
+
+// baselineTolerance absorbs float noise in results recorded to three
+// decimals
+const baselineTolerance = 0.0005
+
+// BaselineEntry is a round's recorded outcome in a --baseline file
+type BaselineEntry struct {
+	RoundID string  `json:"round_id"`
+	Result  float64 `json:"result"`
+	Winner  string  `json:"winner"`
+}
+
+// BaselineChange is a round whose computed outcome differs from the baseline
+type BaselineChange struct {
+	Source         string  `json:"source"`
+	RoundID        string  `json:"round_id"`
+	ExpectedResult float64 `json:"expected_result"`
+	Result         float64 `json:"result"`
+	ExpectedWinner string  `json:"expected_winner"`
+	Winner         string  `json:"winner"`
+}
+
+// BaselineReport compares a batch's computed outcomes with a baseline,
+// independently of whether the rounds passed verification
+type BaselineReport struct {
+	Passed   bool             `json:"passed"`
+	Compared int              `json:"compared"`
+	Changed  []BaselineChange `json:"changed"`
+	// Missing lists baseline rounds the batch did not verify, and New the
+	// verified rounds the baseline does not hold
+	Missing []string `json:"missing,omitempty"`
+	New     []string `json:"new,omitempty"`
+}
+
+// loadBaseline reads a --baseline file: a JSON array of BaselineEntry, or the
+// --json output of an earlier batch run, whose computed outcomes are used
+func loadBaseline(path string) (map[string]BaselineEntry, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read baseline: %v", err)
+	}
+	raw = normalizeInput(raw)
+
+	var entries []BaselineEntry
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		var report BatchReport
+		if err := json.Unmarshal(raw, &report); err != nil {
+			return nil, fmt.Errorf("Failed to parse baseline: %v", err)
+		}
+		for _, round := range report.Rounds {
+			if round.Result != nil {
+				entries = append(entries, BaselineEntry{
+					RoundID: round.Result.RoundID,
+					Result:  round.Result.ComputedResult,
+					Winner:  round.Result.ComputedWinner,
+				})
+			}
+		}
+		if len(entries) == 0 {
+			return nil, fmt.Errorf("baseline holds no round outcomes")
+		}
+	}
+
+	baseline := make(map[string]BaselineEntry, len(entries))
+	for _, entry := range entries {
+		if entry.RoundID == "" {
+			return nil, fmt.Errorf("baseline entry without a round_id")
+		}
+		if _, ok := baseline[entry.RoundID]; ok {
+			return nil, fmt.Errorf("baseline lists round %s twice", entry.RoundID)
+		}
+		baseline[entry.RoundID] = entry
+	}
+	return baseline, nil
+}
+
+// compareBaseline reports every verified round whose computed result or
+// winner differs from its baseline entry, in batch order
+func compareBaseline(report *BatchReport, baseline map[string]BaselineEntry) *BaselineReport {
+	comparison := &BaselineReport{Passed: true, Changed: []BaselineChange{}}
+	seen := make(map[string]bool)
+	for _, round := range report.Rounds {
+		if round.Result == nil {
+			continue
+		}
+		result := round.Result
+		seen[result.RoundID] = true
+		expected, ok := baseline[result.RoundID]
+		if !ok {
+			comparison.New = append(comparison.New, result.RoundID)
+			continue
+		}
+		comparison.Compared++
+		if math.Abs(result.ComputedResult-expected.Result) > baselineTolerance || result.ComputedWinner != expected.Winner {
+			comparison.Changed = append(comparison.Changed, BaselineChange{
+				Source:         round.Source,
+				RoundID:        result.RoundID,
+				ExpectedResult: expected.Result,
+				Result:         result.ComputedResult,
+				ExpectedWinner: expected.Winner,
+				Winner:         result.ComputedWinner,
+			})
+			comparison.Passed = false
+		}
+	}
+	for id := range baseline {
+		if !seen[id] {
+			comparison.Missing = append(comparison.Missing, id)
+		}
+	}
+	sort.Strings(comparison.Missing)
+	return comparison
+}
+
+func printBaselineReport(report *BaselineReport) {
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Printf("📌 Baseline Comparison (%d rounds compared):\n", report.Compared)
+	for _, change := range report.Changed {
+		var diffs []string
+		if math.Abs(change.Result-change.ExpectedResult) > baselineTolerance {
+			diffs = append(diffs, fmt.Sprintf("result %.3f, was %.3f", change.Result, change.ExpectedResult))
+		}
+		if change.Winner != change.ExpectedWinner {
+			diffs = append(diffs, fmt.Sprintf("winner %s, was %s", shortAddress(change.Winner), shortAddress(change.ExpectedWinner)))
+		}
+		fmt.Printf("    🔄 %s (%s): %s\n", change.RoundID, change.Source, strings.Join(diffs, "; "))
+	}
+	if len(report.Missing) > 0 {
+		fmt.Printf("    ➖ Not in this run: %s\n", strings.Join(report.Missing, ", "))
+	}
+	if len(report.New) > 0 {
+		fmt.Printf("    ➕ Not in the baseline: %s\n", strings.Join(report.New, ", "))
+	}
+	if report.Passed {
+		fmt.Println("    ✅ Every compared round has its recorded outcome")
+	} else {
+		fmt.Printf("    ❌ %d round(s) changed outcome since the baseline\n", len(report.Changed))
+	}
+}
@@ -0,0 +1,500 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lazyton/jackpot-verification/verify"
+)
+
+// Batch round statuses
+const (
+	StatusPassed  = "passed"
+	StatusFailed  = "failed"
+	StatusSkipped = "skipped"
+	// StatusUnparseable marks an input that could not be read or decoded;
+	// the rest of the batch is still verified
+	StatusUnparseable = "unparseable"
+)
+
+// BatchRound is the outcome of one input in a batch run
+type BatchRound struct {
+	Source string         `json:"source"`
+	Status string         `json:"status"`
+	Reason string         `json:"reason,omitempty"`
+	Result *verify.Report `json:"result,omitempty"`
+	// Resumed rounds were restored from a --checkpoint instead of verified
+	Resumed bool `json:"resumed,omitempty"`
+
+	// Data is the round as loaded, kept for cross-round checks
+	Data *verify.RoundVerificationData `json:"-"`
+}
+
+// BatchSummary aggregates the outcome of a batch run
+type BatchSummary struct {
+	TotalRounds   int     `json:"total_rounds"`
+	Passed        int     `json:"passed"`
+	Failed        int     `json:"failed"`
+	Skipped       int     `json:"skipped"`
+	Unparseable   int     `json:"unparseable,omitempty"`
+	Resumed       int     `json:"resumed,omitempty"`
+	TotalTimeMS   float64 `json:"total_time_ms"`
+	AverageTimeMS float64 `json:"average_time_ms"`
+
+	// FailuresByCheck counts failed rounds per failing check; a round that
+	// fails several checks is counted under each
+	FailuresByCheck map[string]int `json:"failures_by_check"`
+}
+
+// BatchFailure identifies a round that failed verification
+type BatchFailure struct {
+	Source  string `json:"source"`
+	RoundID string `json:"round_id"`
+	Reason  string `json:"reason"`
+}
+
+// BatchReport is the --json output of a batch run
+type BatchReport struct {
+	VerifierVersion string `json:"verifier_version"`
+	// Date is the day whose rounds were fetched, for --audit-day
+	Date        string              `json:"date,omitempty"`
+	Summary     BatchSummary        `json:"summary"`
+	Failures    []BatchFailure      `json:"failures"`
+	Unparseable []BatchFailure      `json:"unparseable,omitempty"`
+	Chain       *verify.ChainReport `json:"chain,omitempty"`
+	Fairness    *FairnessReport     `json:"fairness,omitempty"`
+	Payouts     *PayoutReport       `json:"payouts,omitempty"`
+	Baseline    *BaselineReport     `json:"baseline,omitempty"`
+	ResultStats *ResultStats        `json:"result_stats,omitempty"`
+	Rounds      []BatchRound        `json:"rounds"`
+}
+
+// redact replaces player addresses in every round result, and in any
+// baseline changes, with pseudonyms
+func (r *BatchReport) redact() {
+	for i, round := range r.Rounds {
+		if round.Result == nil || round.Data == nil {
+			continue
+		}
+		redactor := newRedactor(round.Data.Bets)
+		r.Rounds[i] = round.redacted(redactor)
+		if r.Baseline == nil {
+			continue
+		}
+		for j, change := range r.Baseline.Changed {
+			if change.Source == round.Source {
+				r.Baseline.Changed[j].Winner = redactor.Name(change.Winner)
+				r.Baseline.Changed[j].ExpectedWinner = redactor.Name(change.ExpectedWinner)
+			}
+		}
+	}
+}
+
+// redacted returns the round with the addresses in its result replaced by
+// redactor; rounds without a result are returned unchanged
+func (round BatchRound) redacted(redactor *Redactor) BatchRound {
+	if round.Result != nil {
+		round.Result = redactor.Result(round.Result)
+	}
+	return round
+}
+
+// loadedRounds returns the data of every round the API produced, in input order
+func (r *BatchReport) loadedRounds() []verify.RoundVerificationData {
+	var rounds []verify.RoundVerificationData
+	for _, round := range r.Rounds {
+		if round.Data != nil && round.Data.Success {
+			rounds = append(rounds, *round.Data)
+		}
+	}
+	return rounds
+}
+
+// loadedResults returns the result of every round loadedRounds returns
+// that was verified, in input order
+func (r *BatchReport) loadedResults() []*verify.Report {
+	var results []*verify.Report
+	for _, round := range r.Rounds {
+		if round.Data != nil && round.Data.Success && round.Result != nil {
+			results = append(results, round.Result)
+		}
+	}
+	return results
+}
+
+// batchSource is one round input of a batch: the contents of a file, an
+// archive member or inline JSON, read into Data once when the inputs are
+// collected, or a round id to fetch from the API when Fetch is set
+type batchSource struct {
+	Name  string
+	Data  []byte
+	Fetch *fetchOptions
+	// Inline is set when Name is the inline JSON itself rather than a path
+	Inline bool
+}
+
+// load parses the source's round, fetching it first for an API source
+func (s batchSource) load() (verify.RoundVerificationData, error) {
+	if s.Fetch != nil {
+		data, stats, err := fetchRound(context.Background(), *s.Fetch, s.Name)
+		if err != nil {
+			return data, fmt.Errorf("Failed to fetch round %s from %s: %v", s.Name, stats.URL, err)
+		}
+		return data, nil
+	}
+	return parseRound(s.Data, s.Inline)
+}
+
+// roundSources reads a single file or inline input and returns its sources:
+// one per round when it holds an array of several rounds, else the input
+// itself. Array elements are named file.json[0], file.json[1], ... (or
+// inline[0], ... for inline JSON).
+func roundSources(input string) []batchSource {
+	raw, inline := readInput(input)
+	elements, ok := roundArray(raw)
+	if !ok || len(elements) < 2 {
+		return []batchSource{{Name: input, Data: raw, Inline: inline}}
+	}
+	name := input
+	if inline {
+		name = "inline"
+	}
+	log.Printf("Note: %s is a JSON array of %d rounds; verifying each as a separate round", name, len(elements))
+	sources := make([]batchSource, len(elements))
+	for i, element := range elements {
+		sources[i] = batchSource{Name: fmt.Sprintf("%s[%d]", name, i), Data: element}
+	}
+	return sources
+}
+
+// collectBatchSources expands directories into the .json files they contain
+// and archives into their .json members. It reports whether the arguments
+// describe more than one round: several arguments, a directory of round
+// files, an archive, or a JSON array of several rounds.
+func collectBatchSources(args []string) ([]batchSource, bool, error) {
+	var sources []batchSource
+	batch := false
+	for _, arg := range args {
+		path := cleanInputPath(arg)
+		info, err := os.Stat(path)
+		if err != nil || (!info.IsDir() && !isArchive(path)) {
+			sources = append(sources, roundSources(arg)...)
+			continue
+		}
+		batch = true
+
+		if !info.IsDir() {
+			members, err := readArchive(path)
+			if err != nil {
+				return nil, false, fmt.Errorf("%s: %v", path, err)
+			}
+			sources = append(sources, members...)
+			continue
+		}
+
+		matches, err := filepath.Glob(filepath.Join(path, "*.json"))
+		if err != nil {
+			return nil, false, err
+		}
+		sort.Strings(matches)
+		for _, match := range matches {
+			sources = append(sources, roundSources(match)...)
+		}
+	}
+	return sources, batch || len(sources) > 1, nil
+}
+
+// batchOptions controls how runBatch schedules and reports rounds
+type batchOptions struct {
+	// Workers is the number of rounds verified concurrently (at least 1)
+	Workers int
+	// OnRound is called as each round completes, possibly from several
+	// workers at once, in completion order rather than input order.
+	OnRound func(BatchRound)
+	// Checkpoint, when set, restores rounds completed by an earlier run and
+	// records each newly verified one
+	Checkpoint *checkpoint
+	// Audit, when set, records every check of each newly verified round
+	Audit *auditLog
+	// Drand lists the relays each round's beacon_round is fetched from, as
+	// --drand does for a single round; DrandTimeout bounds each fetch
+	Drand        []string
+	DrandTimeout time.Duration
+}
+
+// runBatch verifies every source and returns the aggregated report with
+// rounds in input order
+func runBatch(sources []batchSource, opts verify.Options, batch batchOptions) (*BatchReport, error) {
+	workers := batch.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	rounds := make([]BatchRound, len(sources))
+	errs := make([]error, len(sources))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				rounds[i], errs[i] = verifySource(sources[i], opts, batch)
+				if round := rounds[i]; errs[i] == nil && batch.Audit != nil && round.Result != nil && !round.Resumed {
+					errs[i] = batch.Audit.record(round.Source, *round.Data, round.Result)
+				}
+				if errs[i] == nil && batch.OnRound != nil {
+					batch.OnRound(rounds[i])
+				}
+			}
+		}()
+	}
+	for i := range sources {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	report := &BatchReport{VerifierVersion: verify.VersionString(), Failures: []BatchFailure{}, Rounds: rounds}
+	report.Summary.FailuresByCheck = map[string]int{}
+	var verifyTime time.Duration
+	verified := 0
+	for _, round := range rounds {
+		switch round.Status {
+		case StatusPassed:
+			report.Summary.Passed++
+		case StatusFailed:
+			report.Summary.Failed++
+			report.Failures = append(report.Failures, BatchFailure{
+				Source:  round.Source,
+				RoundID: round.Result.RoundID,
+				Reason:  round.Reason,
+			})
+			for _, name := range round.Result.FailedChecks() {
+				report.Summary.FailuresByCheck[name]++
+			}
+		case StatusSkipped:
+			report.Summary.Skipped++
+		case StatusUnparseable:
+			report.Summary.Unparseable++
+			report.Unparseable = append(report.Unparseable, BatchFailure{Source: round.Source, Reason: round.Reason})
+		}
+		if round.Resumed {
+			report.Summary.Resumed++
+		}
+		if round.Result != nil && !round.Resumed {
+			verifyTime += round.Result.Duration
+			verified++
+		}
+	}
+
+	report.Summary.TotalRounds = len(report.Rounds)
+	report.Summary.TotalTimeMS = verify.DurationMS(verifyTime)
+	if verified > 0 {
+		report.Summary.AverageTimeMS = report.Summary.TotalTimeMS / float64(verified)
+	}
+	return report, nil
+}
+
+// verifySource loads and verifies a single batch input, or restores it from
+// the checkpoint when an earlier run already verified the same data. An
+// input that cannot be loaded is reported as unparseable, not recorded in
+// the checkpoint, so it is retried once fixed; so is one whose drand beacon
+// cannot be fetched.
+func verifySource(source batchSource, opts verify.Options, batch batchOptions) (BatchRound, error) {
+	cp := batch.Checkpoint
+	round := BatchRound{Source: source.Name}
+	data, err := source.load()
+	if err != nil {
+		round.Status = StatusUnparseable
+		round.Reason = err.Error()
+		return round, nil
+	}
+	if cp != nil {
+		if restored, ok := cp.restore(source.Name, data); ok {
+			return restored, nil
+		}
+	}
+
+	if len(batch.Drand) > 0 && data.Success && data.BeaconRound != 0 {
+		beacons, err := fetchDrandBeacons(context.Background(), batch.Drand, data.BeaconRound, batch.DrandTimeout)
+		if err != nil {
+			round.Status = StatusUnparseable
+			round.Reason = fmt.Sprintf("failed to fetch drand beacon round %d: %v", data.BeaconRound, err)
+			return round, nil
+		}
+		opts.Beacons = beacons
+	}
+
+	round.Data = &data
+	if !data.Success {
+		round.Status = StatusSkipped
+		round.Reason = data.Error
+	} else {
+		round.Result = verify.VerifyRoundWithOptions(data, opts)
+		if round.Result.Passed {
+			round.Status = StatusPassed
+			if round.Result.Partial {
+				round.Reason = "partial: could not check " + strings.Join(round.Result.SkippedChecks(), ", ")
+			}
+		} else {
+			round.Status = StatusFailed
+			round.Reason = strings.Join(round.Result.FailedChecks(), ", ")
+		}
+	}
+
+	if cp != nil {
+		if err := cp.record(round); err != nil {
+			return round, err
+		}
+	}
+	return round, nil
+}
+
+// printBatchReport prints one line per round followed by the summary block
+func printBatchReport(report *BatchReport) {
+	if report.Date != "" {
+		fmt.Printf("📅 Auditing %d rounds played on %s (UTC)\n", len(report.Rounds), report.Date)
+	} else {
+		fmt.Printf("🗂️  Verifying %d rounds\n", len(report.Rounds))
+	}
+	fmt.Println(strings.Repeat("=", 60))
+	for _, round := range report.Rounds {
+		switch round.Status {
+		case StatusPassed:
+			fmt.Printf("✅ Round #%d (%s) passed\n", round.Result.RoundNumber, round.Result.RoundID)
+		case StatusFailed:
+			fmt.Printf("❌ Round #%d (%s) in %s failed checks: %s\n",
+				round.Result.RoundNumber, round.Result.RoundID, round.Source, round.Reason)
+		case StatusSkipped:
+			fmt.Printf("⚠️  %s skipped: %s\n", round.Source, round.Reason)
+		case StatusUnparseable:
+			fmt.Printf("🧩 %s unparseable: %s\n", round.Source, round.Reason)
+		}
+	}
+
+	s := report.Summary
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Printf("📋 Batch Summary: %d/%d rounds passed\n", s.Passed, s.TotalRounds)
+	fmt.Printf("    Total rounds: %d\n", s.TotalRounds)
+	fmt.Printf("    ✅ Passed:    %d\n", s.Passed)
+	fmt.Printf("    ❌ Failed:    %d\n", s.Failed)
+	fmt.Printf("    ⚠️  Skipped:   %d\n", s.Skipped)
+	if s.Unparseable > 0 {
+		fmt.Printf("    🧩 Unparseable: %d (could not be read or decoded)\n", s.Unparseable)
+	}
+	if s.Resumed > 0 {
+		fmt.Printf("    ⏩ Resumed:   %d (from checkpoint, not re-verified)\n", s.Resumed)
+	}
+	fmt.Printf("    ⏱️  Time:      %.3f ms total, %.3f ms average per round\n", s.TotalTimeMS, s.AverageTimeMS)
+	if len(s.FailuresByCheck) > 0 {
+		fmt.Println("    Failures by check:")
+		names := make([]string, 0, len(s.FailuresByCheck))
+		for name := range s.FailuresByCheck {
+			names = append(names, name)
+		}
+		sort.Slice(names, func(i, j int) bool {
+			if s.FailuresByCheck[names[i]] != s.FailuresByCheck[names[j]] {
+				return s.FailuresByCheck[names[i]] > s.FailuresByCheck[names[j]]
+			}
+			return names[i] < names[j]
+		})
+		for _, name := range names {
+			fmt.Printf("      %-17s %d of %d failed rounds\n", name, s.FailuresByCheck[name], s.Failed)
+		}
+	}
+	if len(report.Failures) > 0 {
+		fmt.Println("    Failed rounds:")
+		for _, failure := range report.Failures {
+			fmt.Printf("      ❌ %s (%s): %s\n", failure.Source, failure.RoundID, failure.Reason)
+		}
+	}
+	if len(report.Unparseable) > 0 {
+		fmt.Println("    Unparseable files:")
+		for _, failure := range report.Unparseable {
+			fmt.Printf("      🧩 %s: %s\n", failure.Source, failure.Reason)
+		}
+	}
+	if report.Chain != nil {
+		printChainReport(report.Chain)
+	}
+	if report.Fairness != nil {
+		printFairnessReport(report.Fairness)
+	}
+	if report.Payouts != nil {
+		printPayoutReport(report.Payouts)
+	}
+	if report.Baseline != nil {
+		printBaselineReport(report.Baseline)
+	}
+	if report.ResultStats != nil {
+		printResultStats(report.ResultStats)
+	}
+}
+
+// ndjsonWriter emits one JSON document per line, serializing writes so
+// concurrent workers never interleave their lines
+type ndjsonWriter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func newNDJSONWriter(w io.Writer) *ndjsonWriter {
+	return &ndjsonWriter{enc: json.NewEncoder(w)}
+}
+
+func (w *ndjsonWriter) Write(v interface{}) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.enc.Encode(v); err != nil {
+		log.Printf("Failed to write NDJSON line: %v", err)
+	}
+}
+
+// streamRounds returns a BatchOptions.OnRound that writes each round as an
+// NDJSON line. The report is redacted only once the batch ends, so with
+// redact each streamed round is redacted before it is written.
+func streamRounds(w *ndjsonWriter, redact bool) func(BatchRound) {
+	return func(round BatchRound) {
+		if redact && round.Data != nil {
+			round = round.redacted(newRedactor(round.Data.Bets))
+		}
+		w.Write(round)
+	}
+}
+
+// batchPartial reports whether any round passed only partially
+func batchPartial(report *BatchReport) bool {
+	for _, round := range report.Rounds {
+		if round.Result != nil && round.Result.Partial {
+			return true
+		}
+	}
+	return false
+}
+
+// printBatchOneline prints one key=value line per round with no summary
+func printBatchOneline(report *BatchReport) {
+	for _, round := range report.Rounds {
+		if round.Result == nil {
+			fmt.Printf("source=%s status=%s reason=%q\n", round.Source, round.Status, round.Reason)
+			continue
+		}
+		fmt.Println(formatOneline(round.Result))
+	}
+}
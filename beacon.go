@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/lazyton/jackpot-verification/verify"
+)
+
+// defaultDrandURL is the public drand HTTP relay for the default mainnet
+// chain, suggested in the --drand help
+const defaultDrandURL = "https://api.drand.sh"
+
+// fetchDrandBeacon downloads a beacon round from a drand relay
+func fetchDrandBeacon(ctx context.Context, relay string, round int64, timeout time.Duration) (*verify.DrandBeacon, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	url := fmt.Sprintf("%s/public/%d", strings.TrimRight(relay, "/"), round)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxCommitmentBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read beacon: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, truncate(string(raw), 200))
+	}
+
+	beacon := &verify.DrandBeacon{}
+	if err := json.Unmarshal(raw, beacon); err != nil {
+		return nil, fmt.Errorf("failed to parse beacon: %v", err)
+	}
+	if beacon.Round != round {
+		return nil, fmt.Errorf("relay returned round %d, not %d", beacon.Round, round)
+	}
+	beacon.Relay = relay
+	return beacon, nil
+}
+
+// fetchDrandBeacons fetches the round from every relay; each relay is an
+// independent witness to the value
+func fetchDrandBeacons(ctx context.Context, relays []string, round int64, timeout time.Duration) ([]verify.DrandBeacon, error) {
+	var beacons []verify.DrandBeacon
+	for _, relay := range relays {
+		beacon, err := fetchDrandBeacon(ctx, relay, round, timeout)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", relay, err)
+		}
+		beacons = append(beacons, *beacon)
+	}
+	return beacons, nil
+}
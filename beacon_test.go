@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lazyton/jackpot-verification/verify"
+)
+
+// TestDrandOutsideSingleRound checks that batch and --serve rounds fetch
+// their own beacon from the --drand relays
+func TestDrandOutsideSingleRound(t *testing.T) {
+	const randomness = "9e23a7c3f1b04d5e8a6c2f7b1d3e5a9c0b4f6d8e2a1c3b5d7f9e0a2c4b6d8f1a"
+	relay := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/public/42" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"round": 42, "randomness": %q}`, randomness)
+	}))
+	defer relay.Close()
+
+	beaconRound := func(round int) string {
+		return strings.Replace(prettyRound, `"success": true,`, fmt.Sprintf(`"success": true, "beacon_round": %d, "beacon_value": %q,`, round, randomness), 1)
+	}
+	defaults := verify.Options{Game: verify.GameRegistry[verify.DefaultGameName]}
+	beaconPassed := func(t *testing.T, result *verify.Report) {
+		t.Helper()
+		for _, check := range result.Checks {
+			if check.Name == verify.CheckBeacon {
+				if !check.Passed {
+					t.Errorf("beacon check failed: %s", check.Error)
+				}
+				return
+			}
+		}
+		t.Errorf("no beacon check in %v", result.Checks)
+	}
+
+	t.Run("batch", func(t *testing.T) {
+		batch := batchOptions{Drand: []string{relay.URL}}
+		round, err := verifySource(batchSource{Name: "round.json", Data: []byte(beaconRound(42))}, defaults, batch)
+		if err != nil || round.Result == nil {
+			t.Fatalf("round %+v, error %v", round, err)
+		}
+		beaconPassed(t, round.Result)
+	})
+	t.Run("batch relay error", func(t *testing.T) {
+		batch := batchOptions{Drand: []string{relay.URL}}
+		round, err := verifySource(batchSource{Name: "round.json", Data: []byte(beaconRound(43))}, defaults, batch)
+		if err != nil || round.Status != StatusUnparseable || !strings.Contains(round.Reason, "failed to fetch drand beacon round 43") {
+			t.Fatalf("status %q, reason %q, error %v; want the round unparseable", round.Status, round.Reason, err)
+		}
+	})
+	t.Run("serve", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler := newServeHandler(serveOptions{Verify: defaults, Drand: []string{relay.URL}})
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/verify", strings.NewReader(beaconRound(42))))
+		if !strings.Contains(rec.Body.String(), `{"name":"beacon","passed":true`) {
+			t.Errorf("beacon check did not pass: %s", rec.Body.String())
+		}
+	})
+	t.Run("serve relay error", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler := newServeHandler(serveOptions{Verify: defaults, Drand: []string{relay.URL}, SafeErrors: true})
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/verify", strings.NewReader(beaconRound(43))))
+		if rec.Code != http.StatusBadGateway || strings.Contains(rec.Body.String(), relay.URL) {
+			t.Errorf("status %d: %s; want 502 without the relay detail", rec.Code, rec.Body.String())
+		}
+	})
+}
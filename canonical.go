@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// canonicalDropped are fields that legitimately differ between two builds
+// verifying the same data and are left out of canonical output
+var canonicalDropped = map[string]bool{
+	"verifier_version": true,
+}
+
+// canonicalJSON renders v as compact JSON with sorted keys, integral numbers
+// as integers and all other numbers at six decimals, so that two verifier
+// builds producing the same results emit byte-identical output.
+func canonicalJSON(v interface{}) ([]byte, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var tree interface{}
+	if err := dec.Decode(&tree); err != nil {
+		return nil, err
+	}
+	// Maps encode with sorted keys
+	var out bytes.Buffer
+	enc := json.NewEncoder(&out)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(canonicalize(tree)); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(out.Bytes(), []byte("\n")), nil
+}
+
+func canonicalize(node interface{}) interface{} {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		for key, value := range n {
+			if canonicalDropped[key] {
+				delete(n, key)
+				continue
+			}
+			n[key] = canonicalize(value)
+		}
+		return n
+	case []interface{}:
+		for i, value := range n {
+			n[i] = canonicalize(value)
+		}
+		return n
+	case json.Number:
+		f, err := n.Float64()
+		if err != nil {
+			return n
+		}
+		if f == math.Trunc(f) && math.Abs(f) < 1e15 {
+			return json.Number(strconv.FormatFloat(f, 'f', 0, 64))
+		}
+		return json.Number(strconv.FormatFloat(f, 'f', 6, 64))
+	}
+	return node
+}
+
+// writeCanonical prints v as one canonical JSON line on stdout
+func writeCanonical(v interface{}) {
+	line, err := canonicalJSON(v)
+	if err != nil {
+		fatalf("Failed to encode canonical JSON: %v", err)
+	}
+	fmt.Println(string(line))
+}
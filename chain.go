@@ -0,0 +1,21 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/lazyton/jackpot-verification/verify"
+)
+
+func printChainReport(report *verify.ChainReport) {
+	fmt.Printf("🔗 Chain: %d rounds, #%d to #%d\n", report.Rounds, report.FirstRound, report.LastRound)
+	if report.Passed {
+		fmt.Println("    ✅ Round numbers increase by exactly one, each round links to the previous server hash, and no seed is reused")
+		return
+	}
+	for _, finding := range report.Findings {
+		fmt.Printf("    ❌ %s\n", finding.Message)
+	}
+	if report.FailedRounds > 0 {
+		fmt.Printf("    ❌ %d of the rounds failed their own verification\n", report.FailedRounds)
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/lazyton/jackpot-verification/verify"
+)
+
+// CheckpointEntry is one completed round in a --checkpoint file. The file is
+// NDJSON, appended to as rounds complete, so an interrupted run loses at
+// most the line being written.
+type CheckpointEntry struct {
+	Source string `json:"source"`
+	// Digest is the proof digest of the round data; a source whose data
+	// has changed since it was checkpointed is verified again
+	Digest string `json:"digest"`
+	// Options is the digest of the verify options the round was checked
+	// under; a run with a different game profile or check settings
+	// verifies it again
+	Options string         `json:"options"`
+	Status  string         `json:"status"`
+	Reason  string         `json:"reason,omitempty"`
+	Result  *verify.Report `json:"result,omitempty"`
+}
+
+// checkpoint records completed batch rounds and restores them on resume
+type checkpoint struct {
+	mu      sync.Mutex
+	done    map[string]CheckpointEntry
+	options string
+	file    *os.File
+	enc     *json.Encoder
+}
+
+// openCheckpoint loads the entries already in path, if any, and opens it
+// for appending rounds verified under opts. A truncated final line from a
+// crash is ignored.
+func openCheckpoint(path string, opts verify.Options) (*checkpoint, error) {
+	c := &checkpoint{done: make(map[string]CheckpointEntry), options: optionsDigest(opts)}
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, line := range splitLines(existing) {
+		var entry CheckpointEntry
+		if json.Unmarshal(line, &entry) == nil && entry.Source != "" {
+			c.done[entry.Source] = entry
+		}
+	}
+
+	c.file, err = os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	// Start on a fresh line after a write cut short mid-line
+	if len(existing) > 0 && existing[len(existing)-1] != '\n' {
+		if _, err := c.file.Write([]byte("\n")); err != nil {
+			c.file.Close()
+			return nil, err
+		}
+	}
+	c.enc = json.NewEncoder(c.file)
+	return c, nil
+}
+
+// optionsDigest hashes the options that decide a round's outcome: the game
+// profile (formula, rounding, minimum winning bet, ...), hex case, expected
+// winner, seed strength, partial mode, bet cap, shares, fallback rule, and the
+// commitment and beacons compared against. Options that only add detail to
+// the report, such as tracing and timings, are left out.
+func optionsDigest(opts verify.Options) string {
+	raw, err := json.Marshal(struct {
+		Game               verify.GameConfig           `json:"game"`
+		HexCaseInsensitive bool                        `json:"hex_case_insensitive"`
+		ExpectWinner       string                      `json:"expect_winner"`
+		MinSeedBits        float64                     `json:"min_seed_bits"`
+		Partial            bool                        `json:"partial"`
+		MaxBet             float64                     `json:"max_bet"`
+		AmountsAreShares   bool                        `json:"amounts_are_shares"`
+		FallbackRule       string                      `json:"fallback_rule"`
+		Commitment         *verify.PublishedCommitment `json:"commitment"`
+		Beacons            []verify.DrandBeacon        `json:"beacons"`
+	}{
+		opts.Game, opts.HexCaseInsensitive, opts.ExpectWinner, opts.MinSeedBits, opts.Partial,
+		opts.MaxBet, opts.AmountsAreShares, opts.FallbackRule, opts.Commitment, opts.Beacons,
+	})
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// restore returns the checkpointed outcome of a source whose data and
+// verify options are unchanged since it was recorded
+func (c *checkpoint) restore(source string, data verify.RoundVerificationData) (BatchRound, bool) {
+	c.mu.Lock()
+	entry, ok := c.done[source]
+	c.mu.Unlock()
+	if !ok || entry.Digest != proofDigest(data) || entry.Options == "" || entry.Options != c.options {
+		return BatchRound{}, false
+	}
+	return BatchRound{
+		Source:  source,
+		Status:  entry.Status,
+		Reason:  entry.Reason,
+		Result:  entry.Result,
+		Data:    &data,
+		Resumed: true,
+	}, true
+}
+
+// record appends a completed round to the checkpoint file
+func (c *checkpoint) record(round BatchRound) error {
+	entry := CheckpointEntry{
+		Source:  round.Source,
+		Digest:  proofDigest(*round.Data),
+		Options: c.options,
+		Status:  round.Status,
+		Reason:  round.Reason,
+		Result:  round.Result,
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.done[entry.Source] = entry
+	if err := c.enc.Encode(entry); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %v", err)
+	}
+	return nil
+}
+
+func (c *checkpoint) Close() error {
+	return c.file.Close()
+}
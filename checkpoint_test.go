@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lazyton/jackpot-verification/verify"
+)
+
+func TestCheckpointResumesOnlyUnderSameOptions(t *testing.T) {
+	var data verify.RoundVerificationData
+	if err := json.Unmarshal([]byte(prettyRound), &data); err != nil {
+		t.Fatal(err)
+	}
+	base := verify.Options{Game: verify.GameRegistry[verify.DefaultGameName]}
+	path := filepath.Join(t.TempDir(), "checkpoint.ndjson")
+
+	cp, err := openCheckpoint(path, base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	round := BatchRound{Source: "r1.json", Status: StatusPassed, Data: &data, Result: verify.VerifyRoundWithOptions(data, base)}
+	if err := cp.record(round); err != nil {
+		t.Fatal(err)
+	}
+	cp.Close()
+
+	truncating := base.Game
+	truncating.ResultRounding = verify.RoundingTruncate
+	minBet := base.Game
+	minBet.MinWinningBet = 14
+	tests := []struct {
+		name    string
+		change  func(*verify.Options)
+		resumed bool
+	}{
+		{name: "same options", change: func(*verify.Options) {}, resumed: true},
+		{name: "trace only", change: func(o *verify.Options) { o.Trace, o.Timings = true, true }, resumed: true},
+		{name: "result rounding", change: func(o *verify.Options) { o.Game = truncating }},
+		{name: "minimum winning bet", change: func(o *verify.Options) { o.Game = minBet }},
+		{name: "hex case", change: func(o *verify.Options) { o.HexCaseInsensitive = true }},
+		{name: "bet cap", change: func(o *verify.Options) { o.MaxBet = 10 }},
+		{name: "expected winner", change: func(o *verify.Options) { o.ExpectWinner = "EQA1aaaaaaaa4B2C" }},
+		{name: "fallback rule", change: func(o *verify.Options) { o.FallbackRule = verify.FallbackLast }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := base
+			tt.change(&opts)
+			cp, err := openCheckpoint(path, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer cp.Close()
+			if _, ok := cp.restore("r1.json", data); ok != tt.resumed {
+				t.Errorf("restored = %v, want %v", ok, tt.resumed)
+			}
+		})
+	}
+
+	t.Run("entry without options", func(t *testing.T) {
+		legacy := filepath.Join(t.TempDir(), "legacy.ndjson")
+		line, _ := json.Marshal(CheckpointEntry{Source: "r1.json", Digest: proofDigest(data), Status: StatusPassed})
+		if err := os.WriteFile(legacy, append(line, '\n'), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		cp, err := openCheckpoint(legacy, base)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer cp.Close()
+		if _, ok := cp.restore("r1.json", data); ok {
+			t.Error("restored an entry recorded without its verify options")
+		}
+	})
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/lazyton/jackpot-verification/verify"
+)
+
+const (
+	// ipfsGateway resolves ipfs:// commitment URLs
+	ipfsGateway = "https://ipfs.io/ipfs/"
+	// maxCommitmentBytes bounds a fetched commitment document
+	maxCommitmentBytes = 64 << 10
+)
+
+// CommitmentRecord notes that a next-round server hash was seen before
+// betting opened. The digest binds the commitment to the local timestamp so
+// the record can be archived or published and later compared with check #1.
+type CommitmentRecord struct {
+	Commitment      string `json:"commitment"`
+	Game            string `json:"game"`
+	HashAlgorithm   string `json:"hash_algorithm"`
+	RecordedAt      string `json:"recorded_at"`
+	Digest          string `json:"digest"`
+	VerifierVersion string `json:"verifier_version"`
+}
+
+// recordCommitment validates a next-round commitment and timestamps it
+func recordCommitment(game verify.GameConfig, commitment string, now time.Time) (*CommitmentRecord, error) {
+	commitment = strings.ToLower(strings.TrimSpace(commitment))
+	if err := verify.ValidateCommitment(game, commitment); err != nil {
+		return nil, err
+	}
+
+	record := &CommitmentRecord{
+		Commitment:      commitment,
+		Game:            game.Name,
+		HashAlgorithm:   game.HashAlgorithm,
+		RecordedAt:      now.UTC().Format(time.RFC3339),
+		VerifierVersion: verify.VersionString(),
+	}
+	record.Digest = verify.HashString(strings.Join([]string{record.Game, record.HashAlgorithm, record.Commitment, record.RecordedAt}, "|"))
+	return record, nil
+}
+
+func printCommitmentRecord(record *CommitmentRecord) {
+	fmt.Println("🔏 Recording Next-Round Commitment")
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Printf("    ✅ Well-formed %s commitment: %s\n", record.HashAlgorithm, record.Commitment)
+	fmt.Printf("    🕒 Recorded at: %s\n", record.RecordedAt)
+	fmt.Printf("    🧾 Record digest: %s\n", record.Digest)
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Println("Keep this record. Once the round is revealed, its server hash must equal the commitment above.")
+}
+
+// resolveCommitmentURL maps ipfs://<cid>/<path> to the public gateway
+func resolveCommitmentURL(source string) string {
+	if rest, ok := strings.CutPrefix(source, "ipfs://"); ok {
+		return ipfsGateway + rest
+	}
+	return source
+}
+
+// fetchCommitment downloads a published commitment. The document is either
+// the bare hex hash or a JSON object with a "server_hash" or "commitment"
+// field.
+func fetchCommitment(ctx context.Context, source string, timeout time.Duration) (*verify.PublishedCommitment, error) {
+	commitment := &verify.PublishedCommitment{Source: source, URL: resolveCommitmentURL(source)}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, commitment.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxCommitmentBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commitment: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, truncate(string(raw), 200))
+	}
+
+	hash := strings.TrimSpace(string(raw))
+	var document struct {
+		ServerHash string `json:"server_hash"`
+		Commitment string `json:"commitment"`
+	}
+	if json.Unmarshal(raw, &document) == nil {
+		hash = document.ServerHash
+		if hash == "" {
+			hash = document.Commitment
+		}
+		if hash == "" {
+			return nil, fmt.Errorf("commitment document has no server_hash or commitment field")
+		}
+	}
+	commitment.Hash = strings.ToLower(strings.TrimSpace(hash))
+	return commitment, nil
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// roundsEndpoint lists the ids of the rounds played on a date
+	roundsEndpoint = "/api/jackpot/rounds"
+	// auditDayLayout is the --audit-day date format; days are UTC
+	auditDayLayout = "2006-01-02"
+)
+
+// roundsRequest is the body POSTed to the rounds endpoint; Cursor requests
+// a later page of ids
+type roundsRequest struct {
+	Date   string `json:"date"`
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// roundsPage is one page of round ids from the rounds endpoint
+type roundsPage struct {
+	Success    bool     `json:"success"`
+	Error      string   `json:"error,omitempty"`
+	RoundIDs   []string `json:"round_ids"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+}
+
+// parseAuditDay validates an --audit-day date and returns it normalized
+func parseAuditDay(day string) (string, error) {
+	t, err := time.Parse(auditDayLayout, day)
+	if err != nil {
+		return "", fmt.Errorf("invalid --audit-day %q: want a date such as 2024-01-15", day)
+	}
+	return t.Format(auditDayLayout), nil
+}
+
+// fetchDayRoundIDs lists every round played on day, following next_cursor
+// until the last page. Ids listed twice are kept once, in first-seen order.
+func fetchDayRoundIDs(ctx context.Context, opts fetchOptions, day string) ([]string, error) {
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	var ids []string
+	listed := map[string]bool{}
+	seen := map[string]bool{}
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages >= maxBetPages || seen[cursor] {
+			return nil, fmt.Errorf("pagination did not terminate: cursor %q repeats after %d pages", cursor, pages)
+		}
+		seen[cursor] = true
+
+		page, err := postRounds(ctx, opts, roundsRequest{Date: day, Cursor: cursor})
+		if err != nil {
+			return nil, fmt.Errorf("page %d: %v", pages+1, err)
+		}
+		if !page.Success {
+			return nil, fmt.Errorf("page %d returned an error: %s", pages+1, page.Error)
+		}
+		for _, id := range page.RoundIDs {
+			if !listed[id] {
+				listed[id] = true
+				ids = append(ids, id)
+			}
+		}
+		if page.NextCursor == "" {
+			return ids, nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+// postRounds requests one page of round ids, retrying server errors and
+// network failures like postVerify
+func postRounds(ctx context.Context, opts fetchOptions, body roundsRequest) (roundsPage, error) {
+	delay := opts.retryDelay()
+	for attempt := 0; ; attempt++ {
+		page, status, err := doPostRounds(ctx, opts, body)
+		retryable := status >= http.StatusInternalServerError || (status == 0 && err != nil)
+		if ctx.Err() != nil || !retryable || attempt == fetchRetries {
+			return page, err
+		}
+		select {
+		case <-ctx.Done():
+			return page, err
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// doPostRounds sends the request and decodes the page, returning the HTTP
+// status, or 0 when no response was received
+func doPostRounds(ctx context.Context, opts fetchOptions, body roundsRequest) (roundsPage, int, error) {
+	var page roundsPage
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return page, 0, err
+	}
+
+	url := strings.TrimRight(opts.APIURL, "/") + roundsEndpoint
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return page, 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := opts.client().Do(req)
+	if err != nil {
+		return page, 0, fmt.Errorf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxRoundBytes))
+	if err != nil {
+		return page, resp.StatusCode, fmt.Errorf("failed to read response: %v", err)
+	}
+	if err := json.Unmarshal(raw, &page); err != nil || resp.StatusCode != http.StatusOK {
+		if resp.StatusCode != http.StatusOK {
+			return page, resp.StatusCode, fmt.Errorf("API returned HTTP %d: %s", resp.StatusCode, truncate(string(raw), 200))
+		}
+		return page, resp.StatusCode, fmt.Errorf("failed to parse API response: %v", err)
+	}
+	return page, resp.StatusCode, nil
+}
+
+// daySources lists the rounds played on day as batch sources that are
+// fetched from the API when the batch verifies them
+func daySources(opts fetchOptions, day string) ([]batchSource, error) {
+	ids, err := fetchDayRoundIDs(context.Background(), opts, day)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the rounds of %s from %s%s: %v",
+			day, strings.TrimRight(opts.APIURL, "/"), roundsEndpoint, err)
+	}
+	sources := make([]batchSource, len(ids))
+	for i, id := range ids {
+		sources[i] = batchSource{Name: id, Fetch: &opts}
+	}
+	return sources, nil
+}
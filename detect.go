@@ -0,0 +1,207 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lazyton/jackpot-verification/verify"
+)
+
+// FormulaMatch is how well one game profile reproduces a round
+type FormulaMatch struct {
+	Game              string  `json:"game"`
+	ServerHashMatches bool    `json:"server_hash_matches"`
+	ClientSeedMatches bool    `json:"client_seed_matches"`
+	Result            float64 `json:"result"`
+	ResultMatches     bool    `json:"result_matches"`
+	WinnerMatches     bool    `json:"winner_matches"`
+}
+
+// Matches reports whether the profile reproduces the claimed result and winner
+func (m FormulaMatch) Matches() bool {
+	return m.ResultMatches && m.WinnerMatches
+}
+
+// FormulaReport is the outcome of --detect-formula
+type FormulaReport struct {
+	RoundID     string         `json:"round_id"`
+	RoundNumber int            `json:"round_number"`
+	Claimed     float64        `json:"claimed_result"`
+	Candidates  []FormulaMatch `json:"candidates"`
+	Matching    []string       `json:"matching"`
+
+	VerifierVersion string `json:"verifier_version"`
+}
+
+// detectFormula verifies the round under every registered game profile and
+// reports which ones reproduce the claimed result and winner. Options other
+// than the game (tie-break, fallback rule, ...) apply to every candidate.
+func detectFormula(data verify.RoundVerificationData, opts verify.Options) *FormulaReport {
+	report := &FormulaReport{
+		RoundID:         data.RoundID,
+		RoundNumber:     data.RoundNumber,
+		Claimed:         data.Result,
+		Matching:        []string{},
+		VerifierVersion: verify.VersionString(),
+	}
+	for _, name := range verify.GameNames() {
+		candidate := opts
+		candidate.Game = verify.GameRegistry[name]
+		candidate.Trace = false
+		result := verify.VerifyRoundWithOptions(data, candidate)
+
+		match := FormulaMatch{
+			Game:              name,
+			ServerHashMatches: result.CheckPassed(verify.CheckServerHash),
+			ClientSeedMatches: result.CheckPassed(verify.CheckClientSeed),
+			Result:            result.ComputedResult,
+			ResultMatches:     result.CheckPassed(verify.CheckResult),
+			WinnerMatches:     result.CheckPassed(verify.CheckWinner),
+		}
+		report.Candidates = append(report.Candidates, match)
+		if match.Matches() {
+			report.Matching = append(report.Matching, name)
+		}
+	}
+	return report
+}
+
+func printFormulaReport(report *FormulaReport) {
+	fmt.Printf("🧪 Detecting Formula for Jackpot Round #%d (%s)\n", report.RoundNumber, report.RoundID)
+	fmt.Printf("🎯 Claimed Result: %.3f\n", report.Claimed)
+	fmt.Println(strings.Repeat("=", 60))
+	for _, c := range report.Candidates {
+		icon := "❌"
+		if c.Matches() {
+			icon = "✅"
+		}
+		fmt.Printf("    %s %-20s result %.3f  server_hash %s  client_seed %s  result %s  winner %s\n",
+			icon, c.Game, c.Result, passedLabel(c.ServerHashMatches), passedLabel(c.ClientSeedMatches),
+			passedLabel(c.ResultMatches), passedLabel(c.WinnerMatches))
+	}
+
+	fmt.Println(strings.Repeat("=", 60))
+	switch {
+	case len(report.Matching) == 0:
+		fmt.Println("💀 No registered formula reproduces the claimed result and winner.")
+	case len(report.Matching) == 1 && report.Matching[0] == verify.DefaultGameName:
+		fmt.Println("🎉 The round matches the default formula.")
+	default:
+		fmt.Printf("🎉 The result matches formula %s", strings.Join(report.Matching, ", "))
+		if !contains(report.Matching, verify.DefaultGameName) {
+			fmt.Print(", not the default")
+		}
+		fmt.Println(".")
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// modulusCandidates are the modulus and divisor pairs --detect-modulus
+// tries besides the game's own: the default and its off-by-one, and the same
+// pair one decimal coarser. Each keeps results within about [0, 100].
+var modulusCandidates = []struct {
+	Modulus int64
+	Divisor float64
+}{
+	{100001, 1000},
+	{100000, 1000},
+	{10001, 100},
+	{10000, 100},
+}
+
+// ModulusMatch is the result one modulus and divisor pair yields
+type ModulusMatch struct {
+	Modulus    int64   `json:"modulus"`
+	Divisor    float64 `json:"divisor"`
+	Result     float64 `json:"result"`
+	Matches    bool    `json:"matches"`
+	Configured bool    `json:"configured,omitempty"`
+}
+
+// ModulusReport is the outcome of --detect-modulus
+type ModulusReport struct {
+	RoundID     string         `json:"round_id"`
+	RoundNumber int            `json:"round_number"`
+	Claimed     float64        `json:"claimed_result"`
+	Candidates  []ModulusMatch `json:"candidates"`
+	// Matching lists the moduli that reproduce the claimed result
+	Matching []int64 `json:"matching"`
+
+	VerifierVersion string `json:"verifier_version"`
+}
+
+// detectModulus redraws the result under the game's modulus and a few
+// nearby ones, keeping everything else about the game, to pinpoint a backend
+// that reduces the HMAC by a different modulus. Only the result is compared;
+// the seeds are used as published.
+func detectModulus(data verify.RoundVerificationData, game verify.GameConfig) *ModulusReport {
+	report := &ModulusReport{
+		RoundID:         data.RoundID,
+		RoundNumber:     data.RoundNumber,
+		Claimed:         data.Result,
+		Matching:        []int64{},
+		VerifierVersion: verify.VersionString(),
+	}
+	candidates := append([]struct {
+		Modulus int64
+		Divisor float64
+	}{{game.Modulus, game.Divisor}}, modulusCandidates...)
+
+	message := game.Message(data.ServerSeed, data.ClientSeed, data.RoundNumber, data.PreviousHash)
+	claimed := game.FormatResult(data.Result)
+	for i, c := range candidates {
+		if i > 0 && c.Modulus == game.Modulus && c.Divisor == game.Divisor {
+			continue
+		}
+		candidate := game
+		candidate.Modulus, candidate.Divisor = c.Modulus, c.Divisor
+		d := verify.DeriveResult(candidate, data.ServerSeed, message)
+		match := ModulusMatch{
+			Modulus:    c.Modulus,
+			Divisor:    c.Divisor,
+			Result:     d.Result,
+			Matches:    candidate.FormatResult(d.Result) == claimed,
+			Configured: i == 0,
+		}
+		report.Candidates = append(report.Candidates, match)
+		if match.Matches {
+			report.Matching = append(report.Matching, c.Modulus)
+		}
+	}
+	return report
+}
+
+func printModulusReport(report *ModulusReport) {
+	fmt.Printf("🧪 Detecting Modulus for Jackpot Round #%d (%s)\n", report.RoundNumber, report.RoundID)
+	fmt.Printf("🎯 Claimed Result: %.3f\n", report.Claimed)
+	fmt.Println(strings.Repeat("=", 60))
+	for _, c := range report.Candidates {
+		icon := "❌"
+		if c.Matches {
+			icon = "✅"
+		}
+		configured := ""
+		if c.Configured {
+			configured = " (configured)"
+		}
+		fmt.Printf("    %s modulus %-7d divisor %-5g result %.3f%s\n", icon, c.Modulus, c.Divisor, c.Result, configured)
+	}
+
+	fmt.Println(strings.Repeat("=", 60))
+	switch {
+	case len(report.Candidates) > 0 && report.Candidates[0].Matches:
+		fmt.Println("🎉 The configured modulus reproduces the claimed result.")
+	case len(report.Matching) == 0:
+		fmt.Println("💀 No nearby modulus reproduces the claimed result; the mismatch is not an off-by-one modulus.")
+	default:
+		fmt.Printf("🎉 The claimed result matches modulus %d, not the configured %d.\n", report.Matching[0], report.Candidates[0].Modulus)
+	}
+}
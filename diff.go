@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	ansiDim   = "\033[2m"
+	ansiRed   = "\033[31m"
+	ansiReset = "\033[0m"
+)
+
+// useColor enables ANSI colors in hash diffs printed to stdout; set from
+// --no-color and whether stdout is a terminal
+var useColor = false
+
+// colorSupported reports whether stdout is a terminal and NO_COLOR is unset
+func colorSupported() bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// commonPrefixLen returns the length of the shared prefix of a and b
+func commonPrefixLen(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
+
+// printHashDiff prints two values one above the other so their first
+// difference stands out: with color the shared prefix is dimmed and the
+// diverging suffix is red, otherwise a caret points at the first difference.
+// Color is only used on stdout, the terminal useColor was decided for.
+func printHashDiff(w io.Writer, labelA, a, labelB, b string) {
+	color := useColor && w == io.Writer(os.Stdout)
+	width := len(labelA)
+	if len(labelB) > width {
+		width = len(labelB)
+	}
+	prefix := commonPrefixLen(a, b)
+
+	line := func(label, value string) {
+		fmt.Fprintf(w, "       %-*s ", width+1, label+":")
+		if color {
+			fmt.Fprintf(w, "%s%s%s%s%s%s\n", ansiDim, value[:prefix], ansiReset, ansiRed, value[prefix:], ansiReset)
+		} else {
+			fmt.Fprintln(w, value)
+		}
+	}
+	line(labelA, a)
+	line(labelB, b)
+
+	if !color && a != b {
+		fmt.Fprintf(w, "       %s^ first difference at character %d\n", strings.Repeat(" ", width+2+prefix), prefix+1)
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// FairnessReport aggregates a batch into a headline fairness score.
+// Skipped rounds (no data from the API) are not counted.
+type FairnessReport struct {
+	Rounds       int     `json:"rounds"`
+	Clean        int     `json:"clean"`
+	Partial      int     `json:"partial"`
+	Failed       int     `json:"failed"`
+	CleanPercent float64 `json:"clean_percent"`
+	// FailuresByCheck counts failed rounds per failing check; a round that
+	// fails several checks is counted under each
+	FailuresByCheck map[string]int `json:"failures_by_check"`
+	// Score is the share of verified rounds, 0 to 100, that verified clean,
+	// with partially verified rounds counting half
+	Score float64 `json:"score"`
+}
+
+// scoreFairness computes the fairness report of a batch
+func scoreFairness(report *BatchReport) *FairnessReport {
+	fairness := &FairnessReport{FailuresByCheck: map[string]int{}}
+	for _, round := range report.Rounds {
+		if round.Result == nil {
+			continue
+		}
+		fairness.Rounds++
+		switch {
+		case !round.Result.Passed:
+			fairness.Failed++
+			for _, name := range round.Result.FailedChecks() {
+				fairness.FailuresByCheck[name]++
+			}
+		case round.Result.Partial:
+			fairness.Partial++
+		default:
+			fairness.Clean++
+		}
+	}
+
+	if fairness.Rounds > 0 {
+		n := float64(fairness.Rounds)
+		fairness.CleanPercent = float64(fairness.Clean) / n * 100
+		fairness.Score = math.Round((float64(fairness.Clean)+0.5*float64(fairness.Partial))/n*1000) / 10
+	}
+	return fairness
+}
+
+func printFairnessReport(fairness *FairnessReport) {
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Println("⚖️  Fairness Report:")
+	if fairness.Rounds == 0 {
+		fmt.Println("    No verified rounds to score")
+		return
+	}
+	fmt.Printf("    Fairness score: %.1f / 100\n", fairness.Score)
+	fmt.Printf("    Clean rounds:   %d of %d (%.1f%%)\n", fairness.Clean, fairness.Rounds, fairness.CleanPercent)
+	if fairness.Partial > 0 {
+		fmt.Printf("    Partial rounds: %d (count half)\n", fairness.Partial)
+	}
+	if fairness.Failed > 0 {
+		fmt.Printf("    Failed rounds:  %d\n", fairness.Failed)
+		names := make([]string, 0, len(fairness.FailuresByCheck))
+		for name := range fairness.FailuresByCheck {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("      %s: %d\n", name, fairness.FailuresByCheck[name])
+		}
+	}
+}
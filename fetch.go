@@ -0,0 +1,208 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/lazyton/jackpot-verification/verify"
+)
+
+const (
+	// defaultAPIURL is used when neither --api-url nor JACKPOT_API_URL is set
+	defaultAPIURL = "https://api.lazycoin.app"
+	// verifyEndpoint returns the verification data for a round
+	verifyEndpoint = "/api/jackpot/verify"
+	// apiURLEnv overrides the default API base URL
+	apiURLEnv = "JACKPOT_API_URL"
+	// maxBetPages guards against a server that never stops paginating
+	maxBetPages = 10000
+	// fetchRetries is how many times a request failing with a 5xx status or
+	// a network error is retried; 4xx responses are never retried
+	fetchRetries = 2
+	// fetchRetryDelay is the wait before the first retry, doubled each time
+	fetchRetryDelay = 250 * time.Millisecond
+)
+
+// fetchOptions controls how rounds are fetched from the API
+type fetchOptions struct {
+	APIURL  string
+	Timeout time.Duration
+	Client  *http.Client
+	// RetryDelay overrides fetchRetryDelay, the wait before the first retry
+	RetryDelay time.Duration
+}
+
+// FetchStats describes how a round was assembled from the API
+type FetchStats struct {
+	URL   string `json:"url"`
+	Pages int    `json:"pages"`
+	Bets  int    `json:"bets"`
+}
+
+// verifyRequest is the body POSTed to the verify endpoint; Cursor requests
+// a later page of bets
+type verifyRequest struct {
+	RoundID string `json:"round_id"`
+	Cursor  string `json:"cursor,omitempty"`
+}
+
+// defaultAPIBaseURL returns JACKPOT_API_URL when set, else the public API
+func defaultAPIBaseURL() string {
+	if url := os.Getenv(apiURLEnv); url != "" {
+		return url
+	}
+	return defaultAPIURL
+}
+
+func (o fetchOptions) endpoint() string {
+	return strings.TrimRight(o.APIURL, "/") + verifyEndpoint
+}
+
+func (o fetchOptions) retryDelay() time.Duration {
+	if o.RetryDelay > 0 {
+		return o.RetryDelay
+	}
+	return fetchRetryDelay
+}
+
+func (o fetchOptions) client() *http.Client {
+	if o.Client != nil {
+		return o.Client
+	}
+	return http.DefaultClient
+}
+
+// fetchRound POSTs the round id to the verify endpoint and follows
+// next_cursor until every page of bets has been collected. A response with
+// success=false is returned as-is so the caller can report the API error.
+func fetchRound(ctx context.Context, opts fetchOptions, roundID string) (verify.RoundVerificationData, FetchStats, error) {
+	stats := FetchStats{URL: opts.endpoint()}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	data, err := postVerify(ctx, opts, verifyRequest{RoundID: roundID})
+	if err != nil {
+		return data, stats, err
+	}
+	stats.Pages = 1
+	if !data.Success {
+		return data, stats, nil
+	}
+
+	seen := map[string]bool{}
+	for cursor := data.NextCursor; cursor != ""; {
+		if seen[cursor] || stats.Pages >= maxBetPages {
+			return data, stats, fmt.Errorf("pagination did not terminate: cursor %q repeats after %d pages", cursor, stats.Pages)
+		}
+		seen[cursor] = true
+
+		page, err := postVerify(ctx, opts, verifyRequest{RoundID: roundID, Cursor: cursor})
+		if err != nil {
+			return data, stats, fmt.Errorf("incomplete bet list: page %d (cursor %q): %v", stats.Pages+1, cursor, err)
+		}
+		if !page.Success {
+			return data, stats, fmt.Errorf("incomplete bet list: page %d (cursor %q) returned an error: %s", stats.Pages+1, cursor, page.Error)
+		}
+		stats.Pages++
+		data.Bets = append(data.Bets, page.Bets...)
+		cursor = page.NextCursor
+	}
+	data.NextCursor = ""
+
+	stats.Bets = len(data.Bets)
+	if data.TotalBets > 0 && data.TotalBets != stats.Bets {
+		return data, stats, fmt.Errorf("incomplete bet list: API declared %d bets but %d were fetched across %d pages",
+			data.TotalBets, stats.Bets, stats.Pages)
+	}
+	return data, stats, nil
+}
+
+// postVerify requests one page from the verify endpoint, retrying server
+// errors and network failures with exponential backoff until the attempts
+// or the context run out. The last attempt's outcome is returned.
+func postVerify(ctx context.Context, opts fetchOptions, body verifyRequest) (verify.RoundVerificationData, error) {
+	delay := opts.retryDelay()
+	for attempt := 0; ; attempt++ {
+		data, retryable, err := postVerifyOnce(ctx, opts, body)
+		if !retryable || attempt == fetchRetries {
+			return data, err
+		}
+		select {
+		case <-ctx.Done():
+			return data, err
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// postVerifyOnce performs a single request against the verify endpoint and
+// reports whether its failure is worth retrying
+func postVerifyOnce(ctx context.Context, opts fetchOptions, body verifyRequest) (verify.RoundVerificationData, bool, error) {
+	data, status, err := doPostVerify(ctx, opts, body)
+	if ctx.Err() != nil {
+		return data, false, err
+	}
+	return data, status >= http.StatusInternalServerError || (status == 0 && err != nil), err
+}
+
+// doPostVerify sends the request and decodes the response, returning the
+// HTTP status, or 0 when no response was received
+func doPostVerify(ctx context.Context, opts fetchOptions, body verifyRequest) (verify.RoundVerificationData, int, error) {
+	var data verify.RoundVerificationData
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return data, 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, opts.endpoint(), bytes.NewReader(payload))
+	if err != nil {
+		return data, 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := opts.client().Do(req)
+	if err != nil {
+		return data, 0, fmt.Errorf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxRoundBytes))
+	if err != nil {
+		return data, resp.StatusCode, fmt.Errorf("failed to read response: %v", err)
+	}
+
+	if err := json.Unmarshal(raw, &data); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return data, resp.StatusCode, fmt.Errorf("API returned HTTP %d: %s", resp.StatusCode, truncate(string(raw), 200))
+		}
+		return data, resp.StatusCode, fmt.Errorf("failed to parse API response: %v", err)
+	}
+	// Error payloads may come with a non-200 status; surface their message
+	if resp.StatusCode != http.StatusOK && data.Success {
+		return data, resp.StatusCode, fmt.Errorf("API returned HTTP %d", resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK && data.Error == "" {
+		data.Error = fmt.Sprintf("HTTP %d", resp.StatusCode)
+	}
+	return data, resp.StatusCode, nil
+}
+
+func truncate(s string, n int) string {
+	s = strings.TrimSpace(s)
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lazyton/jackpot-verification/verify"
+)
+
+func TestFetchRound(t *testing.T) {
+	page := func(cursor string, bets ...string) verify.RoundVerificationData {
+		data := verify.RoundVerificationData{Success: true, RoundID: "r1", NextCursor: cursor, TotalBets: 3}
+		for _, addr := range bets {
+			data.Bets = append(data.Bets, verify.VerificationBet{PlayerAddress: addr, Amount: 1})
+		}
+		return data
+	}
+
+	tests := []struct {
+		name     string
+		handler  func(w http.ResponseWriter, req verifyRequest, n int32)
+		timeout  time.Duration
+		wantErr  string
+		wantAPI  string
+		wantBets int
+		requests int32
+	}{
+		{
+			name: "paginated",
+			handler: func(w http.ResponseWriter, req verifyRequest, n int32) {
+				if req.Cursor == "" {
+					json.NewEncoder(w).Encode(page("c2", "EQa", "EQb"))
+					return
+				}
+				json.NewEncoder(w).Encode(page("", "EQc"))
+			},
+			wantBets: 3,
+			requests: 2,
+		},
+		{
+			name: "server errors exhaust the retries",
+			handler: func(w http.ResponseWriter, req verifyRequest, n int32) {
+				http.Error(w, "upstream unavailable", http.StatusBadGateway)
+			},
+			wantErr:  "API returned HTTP 502: upstream unavailable",
+			requests: fetchRetries + 1,
+		},
+		{
+			name: "server errors then success",
+			handler: func(w http.ResponseWriter, req verifyRequest, n int32) {
+				if n <= fetchRetries {
+					http.Error(w, "upstream unavailable", http.StatusServiceUnavailable)
+					return
+				}
+				json.NewEncoder(w).Encode(page("", "EQa", "EQb", "EQc"))
+			},
+			wantBets: 3,
+			requests: fetchRetries + 1,
+		},
+		{
+			name: "dropped connection then success",
+			handler: func(w http.ResponseWriter, req verifyRequest, n int32) {
+				if n == 1 {
+					conn, _, err := w.(http.Hijacker).Hijack()
+					if err == nil {
+						conn.Close()
+					}
+					return
+				}
+				json.NewEncoder(w).Encode(page("", "EQa", "EQb", "EQc"))
+			},
+			wantBets: 3,
+			requests: 2,
+		},
+		{
+			name: "server error on a later page is retried",
+			handler: func(w http.ResponseWriter, req verifyRequest, n int32) {
+				switch {
+				case req.Cursor == "":
+					json.NewEncoder(w).Encode(page("c2", "EQa", "EQb"))
+				case n == 2:
+					w.WriteHeader(http.StatusInternalServerError)
+				default:
+					json.NewEncoder(w).Encode(page("", "EQc"))
+				}
+			},
+			wantBets: 3,
+			requests: 3,
+		},
+		{
+			name: "client error payload",
+			handler: func(w http.ResponseWriter, req verifyRequest, n int32) {
+				w.WriteHeader(http.StatusNotFound)
+				json.NewEncoder(w).Encode(verify.RoundVerificationData{Error: "round not found"})
+			},
+			wantAPI:  "round not found",
+			requests: 1,
+		},
+		{
+			name: "client error without payload",
+			handler: func(w http.ResponseWriter, req verifyRequest, n int32) {
+				w.WriteHeader(http.StatusForbidden)
+				json.NewEncoder(w).Encode(map[string]bool{"success": false})
+			},
+			wantAPI:  "HTTP 403",
+			requests: 1,
+		},
+		{
+			name: "malformed body",
+			handler: func(w http.ResponseWriter, req verifyRequest, n int32) {
+				w.Write([]byte(`{"success": true, "bets": [`))
+			},
+			wantErr:  "failed to parse API response",
+			requests: 1,
+		},
+		{
+			name: "malformed later page",
+			handler: func(w http.ResponseWriter, req verifyRequest, n int32) {
+				if req.Cursor == "" {
+					json.NewEncoder(w).Encode(page("c2", "EQa"))
+					return
+				}
+				w.Write([]byte("<html>"))
+			},
+			wantErr:  "incomplete bet list: page 2",
+			requests: 2,
+		},
+		{
+			name: "timeout",
+			handler: func(w http.ResponseWriter, req verifyRequest, n int32) {
+				time.Sleep(200 * time.Millisecond)
+				json.NewEncoder(w).Encode(page("", "EQa", "EQb", "EQc"))
+			},
+			timeout:  20 * time.Millisecond,
+			wantErr:  "request failed",
+			requests: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := atomic.AddInt32(&requests, 1)
+				if r.Method != http.MethodPost || r.URL.Path != verifyEndpoint {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				var req verifyRequest
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RoundID != "r1" {
+					t.Errorf("bad request body: %+v, %v", req, err)
+				}
+				tt.handler(w, req, n)
+			}))
+			defer server.Close()
+
+			opts := fetchOptions{APIURL: server.URL + "/", Timeout: tt.timeout, RetryDelay: time.Millisecond}
+			data, stats, err := fetchRound(context.Background(), opts, "r1")
+
+			switch {
+			case tt.wantErr != "":
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+				}
+			case err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.wantAPI != "":
+				if data.Success || data.Error != tt.wantAPI {
+					t.Errorf("success=%v error=%q, want API error %q", data.Success, data.Error, tt.wantAPI)
+				}
+			default:
+				if len(data.Bets) != tt.wantBets || stats.Bets != tt.wantBets || data.NextCursor != "" {
+					t.Errorf("got %d bets (stats %d, cursor %q), want %d", len(data.Bets), stats.Bets, data.NextCursor, tt.wantBets)
+				}
+			}
+			if got := atomic.LoadInt32(&requests); got != tt.requests {
+				t.Errorf("server saw %d requests, want %d", got, tt.requests)
+			}
+		})
+	}
+}
+
+func TestFetchRoundRepeatingCursor(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(verify.RoundVerificationData{Success: true, RoundID: "r1", NextCursor: "again"})
+	}))
+	defer server.Close()
+
+	_, _, err := fetchRound(context.Background(), fetchOptions{APIURL: server.URL}, "r1")
+	if err == nil || !strings.Contains(err.Error(), "pagination did not terminate") {
+		t.Fatalf("error = %v, want pagination did not terminate", err)
+	}
+}
+
+func TestFetchRoundRetryStopsAtDeadline(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		http.Error(w, "upstream unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	began := time.Now()
+	opts := fetchOptions{APIURL: server.URL, Timeout: 50 * time.Millisecond, RetryDelay: time.Hour}
+	_, _, err := fetchRound(context.Background(), opts, "r1")
+	if err == nil || !strings.Contains(err.Error(), "HTTP 503") {
+		t.Fatalf("error = %v, want the last HTTP 503", err)
+	}
+	if elapsed := time.Since(began); elapsed > 5*time.Second {
+		t.Errorf("fetch took %v; the backoff should end with the deadline", elapsed)
+	}
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("server saw %d requests, want 1 before the deadline", got)
+	}
+}
+
+func TestFetchDayRoundIDsRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures int32
+		status   int
+		wantErr  string
+		requests int32
+	}{
+		{name: "server errors then success", failures: fetchRetries, status: http.StatusBadGateway, requests: fetchRetries + 1},
+		{name: "server errors exhaust the retries", failures: fetchRetries + 1, status: http.StatusBadGateway, wantErr: "HTTP 502", requests: fetchRetries + 1},
+		{name: "client error is not retried", failures: 1, status: http.StatusBadRequest, wantErr: "HTTP 400", requests: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if n := atomic.AddInt32(&requests, 1); n <= tt.failures {
+					http.Error(w, "unavailable", tt.status)
+					return
+				}
+				json.NewEncoder(w).Encode(roundsPage{Success: true, RoundIDs: []string{"r1", "r2"}})
+			}))
+			defer server.Close()
+
+			ids, err := fetchDayRoundIDs(context.Background(), fetchOptions{APIURL: server.URL, RetryDelay: time.Millisecond}, "2024-01-15")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+				}
+			} else if err != nil || len(ids) != 2 {
+				t.Fatalf("ids = %v, %v; want r1 and r2", ids, err)
+			}
+			if got := atomic.LoadInt32(&requests); got != tt.requests {
+				t.Errorf("server saw %d requests, want %d", got, tt.requests)
+			}
+		})
+	}
+}
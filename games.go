@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/lazyton/jackpot-verification/verify"
+)
+
+// loadGamesFile registers the profiles defined in a JSON file containing an
+// array of game configurations. File profiles override built-ins of the same name.
+func loadGamesFile(path string) error {
+	data, err := readInputFile(path)
+	if err != nil {
+		return err
+	}
+	return verify.RegisterGames(data)
+}
+
+func printGames() {
+	for _, name := range verify.GameNames() {
+		game := verify.GameRegistry[name]
+		fmt.Printf("%s\n", game.Name)
+		if game.Description != "" {
+			fmt.Printf("    %s\n", game.Description)
+		}
+		mode, order, rule, rounding := game.ClientSeedMode, game.BetOrder, game.WinnerRule, game.ResultRounding
+		rangeOrder := game.RangeOrder
+		if mode == "" {
+			mode = verify.ClientSeedSHA256
+		}
+		if order == "" {
+			order = verify.BetOrderSorted
+		}
+		if rangeOrder == "" {
+			rangeOrder = verify.BetOrderSorted
+		}
+		if rule == "" {
+			rule = verify.WinnerRuleRange
+		}
+		if rounding == "" {
+			rounding = verify.RoundingRound
+		}
+		fmt.Printf("    hash=%s modulus=%d divisor=%g message=%s tie_break=%t client_seed=%s bet_order=%s range_order=%s winner_rule=%s rounding=%s\n",
+			game.HashAlgorithm, game.Modulus, game.Divisor, game.MessageFormat, game.TieBreak, mode, order, rangeOrder, rule, rounding)
+	}
+}
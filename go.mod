@@ -0,0 +1,3 @@
+module github.com/lazyton/jackpot-verification
+
+go 1.20
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lazyton/jackpot-verification/verify"
+)
+
+// GrindingProbe is the outcome of re-drawing a round without one bet
+type GrindingProbe struct {
+	Index      int     `json:"index"`
+	Player     string  `json:"player"`
+	Amount     float64 `json:"amount"`
+	ClientSeed string  `json:"client_seed"`
+	Result     float64 `json:"result"`
+	Winner     string  `json:"winner"`
+	Pivotal    bool    `json:"pivotal"`
+	Operator   bool    `json:"operator,omitempty"`
+	Error      string  `json:"error,omitempty"`
+}
+
+// GrindingReport lists, for every bet of a round, who would have won had
+// that bet not been placed
+type GrindingReport struct {
+	RoundID     string          `json:"round_id"`
+	RoundNumber int             `json:"round_number"`
+	Result      float64         `json:"result"`
+	Winner      string          `json:"winner"`
+	Probes      []GrindingProbe `json:"probes"`
+	// Flagged is set when an operator bet was pivotal
+	Flagged bool `json:"flagged"`
+
+	VerifierVersion string `json:"verifier_version"`
+}
+
+// Pivotal returns the probes whose removal changed the winner
+func (r *GrindingReport) Pivotal() []GrindingProbe {
+	var pivotal []GrindingProbe
+	for _, probe := range r.Probes {
+		if probe.Pivotal {
+			pivotal = append(pivotal, probe)
+		}
+	}
+	return pivotal
+}
+
+// detectGrinding removes each bet in turn and redraws the round from the
+// revealed server seed. The client seed covers every bet, so an operator
+// who can add or drop a small bet of their own can choose between several
+// draws; a pivotal operator bet is the trace such grinding leaves.
+func detectGrinding(data verify.RoundVerificationData, game verify.GameConfig, operators []string) *GrindingReport {
+	report := &GrindingReport{
+		RoundID:         data.RoundID,
+		RoundNumber:     data.RoundNumber,
+		VerifierVersion: verify.VersionString(),
+	}
+	isOperator := make(map[string]bool)
+	for _, address := range operators {
+		isOperator[address] = true
+	}
+
+	_, report.Result, report.Winner, _ = redraw(data, game)
+	for i, bet := range data.Bets {
+		without := data
+		without.Bets = append(append([]verify.VerificationBet{}, data.Bets[:i]...), data.Bets[i+1:]...)
+		if without.RangeDenominator > 0 {
+			without.RangeDenominator -= bet.Amount
+		}
+
+		probe := GrindingProbe{
+			Index:    i,
+			Player:   bet.PlayerAddress,
+			Amount:   bet.Amount,
+			Operator: isOperator[bet.PlayerAddress],
+		}
+		if len(without.Bets) == 0 {
+			probe.Error = "no bets left"
+		} else {
+			var err error
+			probe.ClientSeed, probe.Result, probe.Winner, err = redraw(without, game)
+			if err != nil {
+				probe.Error = err.Error()
+			}
+			probe.Pivotal = err == nil && probe.Winner != report.Winner
+		}
+		if probe.Pivotal && probe.Operator {
+			report.Flagged = true
+		}
+		report.Probes = append(report.Probes, probe)
+	}
+	return report
+}
+
+// redraw recomputes the client seed, result and winner of a round from its
+// bets and server seed
+func redraw(data verify.RoundVerificationData, game verify.GameConfig) (clientSeed string, result float64, winner string, err error) {
+	clientSeed = verify.RoundClientSeed(game, data, nil)
+	result = verify.CalculateResultTrace(game, data.ServerSeed, clientSeed, data.RoundNumber, data.PreviousHash, nil)
+	winner, err = verify.SelectRoundWinner(game, data, result)
+	return clientSeed, result, winner, err
+}
+
+// redact replaces player addresses in the report with pseudonyms
+func (r *GrindingReport) redact(redactor *Redactor) {
+	r.Winner = redactor.Name(r.Winner)
+	for i := range r.Probes {
+		r.Probes[i].Player = redactor.Name(r.Probes[i].Player)
+		r.Probes[i].Winner = redactor.Name(r.Probes[i].Winner)
+	}
+}
+
+func printGrindingReport(report *GrindingReport) {
+	fmt.Printf("🔬 Grinding Analysis for Jackpot Round #%d (%s)\n", report.RoundNumber, report.RoundID)
+	fmt.Printf("🎯 Recomputed Result: %.3f, winner %s\n", report.Result, report.Winner)
+	fmt.Println(strings.Repeat("=", 60))
+	for _, probe := range report.Probes {
+		label := ""
+		if probe.Operator {
+			label = " [operator]"
+		}
+		switch {
+		case probe.Error != "":
+			fmt.Printf("    ⚠️  Without bet %d by %s%s: %s\n", probe.Index+1, probe.Player, label, probe.Error)
+		case probe.Pivotal:
+			fmt.Printf("    ❗ Without bet %d by %s%s (%.3f TON): result %.3f, winner %s\n",
+				probe.Index+1, probe.Player, label, probe.Amount, probe.Result, probe.Winner)
+		default:
+			fmt.Printf("    ✅ Without bet %d by %s%s (%.3f TON): same winner\n",
+				probe.Index+1, probe.Player, label, probe.Amount)
+		}
+	}
+
+	fmt.Println(strings.Repeat("=", 60))
+	pivotal := report.Pivotal()
+	switch {
+	case report.Flagged:
+		fmt.Println("🚨 An operator bet was pivotal: without it the round would have had a different winner.")
+		fmt.Println("   This is consistent with client-seed grinding by the operator.")
+	case len(pivotal) > 0:
+		fmt.Printf("ℹ️  %d of %d bets were pivotal; none belong to a listed operator.\n", len(pivotal), len(report.Probes))
+	default:
+		fmt.Println("✅ No single bet changed the winner.")
+	}
+}
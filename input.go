@@ -0,0 +1,248 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/lazyton/jackpot-verification/verify"
+)
+
+// Input kinds for --input. Auto tries the argument as a file path, then as
+// inline JSON; the others force one interpretation.
+const (
+	InputAuto   = "auto"
+	InputFile   = "file"
+	InputInline = "inline"
+	InputStdin  = "stdin"
+	InputURL    = "url"
+)
+
+// maxRoundBytes bounds a round read from a URL
+const maxRoundBytes = 64 << 20
+
+// utf8BOM is prepended by some Windows editors and is not valid JSON
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// cleanInputPath strips the whitespace and surrounding quotes that Windows
+// "Copy as path" and some shells leave around paths containing spaces.
+func cleanInputPath(path string) string {
+	path = strings.TrimSpace(path)
+	if len(path) >= 2 {
+		first, last := path[0], path[len(path)-1]
+		if (first == '"' && last == '"') || (first == '\'' && last == '\'') {
+			path = strings.TrimSpace(path[1 : len(path)-1])
+		}
+	}
+	return path
+}
+
+// readInputFile reads a round file and normalizes it so that files saved on
+// Windows (BOM, CRLF line endings) parse identically to their LF versions.
+func readInputFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		cleaned := cleanInputPath(path)
+		if cleaned == path {
+			return nil, err
+		}
+		if data, err = os.ReadFile(cleaned); err != nil {
+			return nil, err
+		}
+	}
+	return normalizeInput(data), nil
+}
+
+// normalizeInput removes a leading BOM and converts CRLF line endings to LF
+func normalizeInput(data []byte) []byte {
+	data = bytes.TrimPrefix(data, utf8BOM)
+	return bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+}
+
+// splitLines splits line-delimited input on both \n and \r\n, dropping blank
+// lines, for formats such as NDJSON where each line is a separate document.
+func splitLines(data []byte) [][]byte {
+	var lines [][]byte
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimSuffix(line, []byte("\r"))
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// readInput reads input as a file path, falling back to treating it as an
+// inline JSON string, which it reports
+func readInput(input string) (raw []byte, inline bool) {
+	if raw, err := readInputFile(input); err == nil {
+		return raw, false
+	}
+	return []byte(input), true
+}
+
+// loadRound reads a round from a file path, falling back to treating the
+// input as an inline JSON string.
+func loadRound(input string) (verify.RoundVerificationData, error) {
+	raw, inline := readInput(input)
+	return parseRound(raw, inline)
+}
+
+// parseRound decodes a round read by readInput, naming where it came from
+// in the error
+func parseRound(raw []byte, inline bool) (verify.RoundVerificationData, error) {
+	data, err := decodeRound(raw)
+	if err != nil && inline {
+		return data, fmt.Errorf("Failed to parse JSON string: %v", err)
+	} else if err != nil {
+		return data, fmt.Errorf("Failed to parse JSON from file: %v", err)
+	}
+	return data, nil
+}
+
+// loadRoundAs reads a round interpreting input as the given kind, with no
+// fallback to another interpretation. Stdin ignores input; a URL is fetched
+// with a GET request bounded by timeout.
+func loadRoundAs(kind, input string, timeout time.Duration) (verify.RoundVerificationData, error) {
+	if kind == "" || kind == InputAuto {
+		return loadRound(input)
+	}
+
+	var raw []byte
+	var err error
+	switch kind {
+	case InputFile:
+		raw, err = readInputFile(input)
+	case InputInline:
+		raw = normalizeInput([]byte(input))
+	case InputStdin:
+		if raw, err = io.ReadAll(os.Stdin); err == nil {
+			raw = normalizeInput(raw)
+		}
+	case InputURL:
+		raw, err = readInputURL(input, timeout)
+	default:
+		err = fmt.Errorf("unknown input kind %q (want %s, %s, %s, %s or %s)", kind, InputAuto, InputFile, InputInline, InputStdin, InputURL)
+	}
+	if err != nil {
+		return verify.RoundVerificationData{}, fmt.Errorf("Failed to read %s input: %v", kind, err)
+	}
+
+	data, err := decodeRound(raw)
+	if err != nil {
+		return data, fmt.Errorf("Failed to parse JSON from %s input: %v", kind, err)
+	}
+	return data, nil
+}
+
+// readInputURL downloads a round document with a GET request
+func readInputURL(url string, timeout time.Duration) ([]byte, error) {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxRoundBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, truncate(string(raw), 200))
+	}
+	return normalizeInput(raw), nil
+}
+
+// decodeRound parses a round object. A one-element array, as produced by
+// copying a round out of a list response, is unwrapped with a note; longer
+// arrays are verified as a batch and never reach here via the CLI.
+func decodeRound(raw []byte) (verify.RoundVerificationData, error) {
+	var data verify.RoundVerificationData
+	if elements, ok := roundArray(raw); ok {
+		switch len(elements) {
+		case 0:
+			return data, fmt.Errorf("input is an empty JSON array, expected a round object {...}")
+		case 1:
+			log.Printf("Note: input is a JSON array holding one round; verifying that round")
+			raw = elements[0]
+		default:
+			return data, fmt.Errorf("input is a JSON array of %d rounds, expected a single round object {...}", len(elements))
+		}
+	}
+	err := json.Unmarshal(raw, &data)
+	return data, err
+}
+
+// roundArray returns the elements of raw when its top-level value is a JSON
+// array, or an object wrapping one as {"rounds": [...]}, rather than a single
+// round object. Only the first non-whitespace byte is peeked at before the
+// input is decoded as one or the other, and an object without a "rounds" key
+// anywhere in its text is left for decodeRound without being decoded here.
+func roundArray(raw []byte) ([]json.RawMessage, bool) {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 {
+		return nil, false
+	}
+	if trimmed[0] == '{' {
+		if !bytes.Contains(trimmed, []byte(`"rounds"`)) {
+			return nil, false
+		}
+		return wrappedRounds(trimmed)
+	}
+	if trimmed[0] != '[' {
+		return nil, false
+	}
+	var elements []json.RawMessage
+	if err := json.Unmarshal(trimmed, &elements); err != nil {
+		return nil, false
+	}
+	return elements, true
+}
+
+// wrappedRounds returns the rounds of an object of the form {"rounds": [...]}.
+// A --json batch report also has a rounds array, of results rather than
+// round data, so objects carrying a summary are not unwrapped.
+func wrappedRounds(raw []byte) ([]json.RawMessage, bool) {
+	var wrapper struct {
+		Rounds  []json.RawMessage `json:"rounds"`
+		Summary json.RawMessage   `json:"summary"`
+	}
+	if err := json.Unmarshal(raw, &wrapper); err != nil || wrapper.Rounds == nil || wrapper.Summary != nil {
+		return nil, false
+	}
+	return wrapper.Rounds, true
+}
+
+// loadRounds loads every source in order, skipping rounds the API could not
+// produce (success=false)
+func loadRounds(sources []batchSource) ([]verify.RoundVerificationData, error) {
+	var rounds []verify.RoundVerificationData
+	for _, source := range sources {
+		data, err := source.load()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", source.Name, err)
+		}
+		if data.Success {
+			rounds = append(rounds, data)
+		}
+	}
+	return rounds, nil
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lazyton/jackpot-verification/verify"
+)
+
+// prettyRound is a valid round as an editor saves it, one field per line
+const prettyRound = `{
+  "success": true,
+  "round_id": "round-1",
+  "round_number": 1,
+  "server_seed": "3f9a1c7e5b2d8a4f6c0e9b1d7a3f5c8e2b4d6f0a1c3e5b7d9f2a4c6e8b0d1f3a",
+  "server_hash": "e72430b6bf09ac29e97d6e15d9dd52062766cc26fd987d786b354f3b1c39ae03",
+  "client_seed": "1782ef25d832791252725061c8b7a5a7e76c80facd4594b9554f3ac617f57754",
+  "previous_hash": "",
+  "bets": [
+    {"player_address": "EQC3zzzzzzzz6D4E", "amount": 15, "gift_id": "g3"},
+    {"player_address": "EQA1aaaaaaaa4B2C", "amount": 13.75, "gift_id": "g1"},
+    {"player_address": "EQB2bbbbbbbb5C3D", "amount": 15.92, "gift_id": ""}
+  ],
+  "result": 54.898,
+  "winner_address": "EQB2bbbbbbbb5C3D",
+  "total_pot": 44.67
+}
+`
+
+func TestLoadRoundLineEndings(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	crlf := strings.ReplaceAll(prettyRound, "\n", "\r\n")
+	lfPath := write("round.json", prettyRound)
+
+	want, err := loadRound(lfPath)
+	if err != nil {
+		t.Fatalf("LF file: %v", err)
+	}
+	wantReport := verify.VerifyRoundWithOptions(want, verify.Options{Game: verify.GameRegistry[verify.DefaultGameName]})
+	if !wantReport.Passed {
+		t.Fatalf("LF round failed: %v", wantReport.FailedChecks())
+	}
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "CRLF file", input: write("round crlf.json", crlf)},
+		{name: "CRLF file with BOM", input: write("round bom.json", "\xEF\xBB\xBF"+crlf)},
+		{name: "quoted path with spaces", input: `"` + filepath.Join(dir, "round crlf.json") + `"`},
+		{name: "padded path", input: "  " + filepath.Join(dir, "round crlf.json") + " "},
+		{name: "inline CRLF", input: crlf},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := loadRound(tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("round differs from the LF version:\n got %+v\nwant %+v", got, want)
+			}
+			report := verify.VerifyRoundWithOptions(got, verify.Options{Game: verify.GameRegistry[verify.DefaultGameName]})
+			if !reflect.DeepEqual(report.Checks, wantReport.Checks) || report.Passed != wantReport.Passed {
+				t.Errorf("checks differ from the LF version:\n got %+v\nwant %+v", report.Checks, wantReport.Checks)
+			}
+		})
+	}
+}
+
+func TestSplitLines(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{name: "LF", input: "{\"a\":1}\n{\"b\":2}\n", want: []string{`{"a":1}`, `{"b":2}`}},
+		{name: "CRLF", input: "{\"a\":1}\r\n{\"b\":2}\r\n", want: []string{`{"a":1}`, `{"b":2}`}},
+		{name: "mixed with blank lines", input: "{\"a\":1}\r\n\r\n  \n{\"b\":2}", want: []string{`{"a":1}`, `{"b":2}`}},
+		{name: "empty", input: "\r\n", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, line := range splitLines([]byte(tt.input)) {
+				got = append(got, string(line))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitLines(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollectBatchSources(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	single := write("single.json", prettyRound)
+	array := write("array.json", "["+prettyRound+","+prettyRound+"]")
+	wrapped := write("wrapped.json", `{"rounds": [`+prettyRound+","+prettyRound+"]}")
+	one := write("one.json", "["+prettyRound+"]")
+	rounds := filepath.Join(dir, "rounds")
+	if err := os.Mkdir(rounds, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(rounds, "r.json"), []byte(prettyRound), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		args      []string
+		wantNames []string
+		wantBatch bool
+	}{
+		{name: "single round file", args: []string{single}, wantNames: []string{single}},
+		{name: "one-element array", args: []string{one}, wantNames: []string{one}},
+		{name: "array of rounds", args: []string{array}, wantNames: []string{array + "[0]", array + "[1]"}, wantBatch: true},
+		{name: "wrapped rounds", args: []string{wrapped}, wantNames: []string{wrapped + "[0]", wrapped + "[1]"}, wantBatch: true},
+		{name: "inline array", args: []string{"[" + prettyRound + "," + prettyRound + "]"}, wantNames: []string{"inline[0]", "inline[1]"}, wantBatch: true},
+		{name: "directory of one round", args: []string{rounds}, wantNames: []string{filepath.Join(rounds, "r.json")}, wantBatch: true},
+		{name: "several files", args: []string{single, one}, wantNames: []string{single, one}, wantBatch: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sources, batch, err := collectBatchSources(tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, source := range sources {
+				names = append(names, source.Name)
+			}
+			if !reflect.DeepEqual(names, tt.wantNames) || batch != tt.wantBatch {
+				t.Fatalf("sources %q, batch %v; want %q, batch %v", names, batch, tt.wantNames, tt.wantBatch)
+			}
+		})
+	}
+
+	t.Run("sources are read once", func(t *testing.T) {
+		path := write("gone.json", prettyRound)
+		sources, _, err := collectBatchSources([]string{path})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Remove(path); err != nil {
+			t.Fatal(err)
+		}
+		data, err := sources[0].load()
+		if err != nil || data.RoundID != "round-1" {
+			t.Errorf("load after the file was removed = %q, %v; want round-1 from the contents already read", data.RoundID, err)
+		}
+	})
+
+	t.Run("parse errors name the input kind", func(t *testing.T) {
+		bad := write("bad.json", "{")
+		for input, want := range map[string]string{bad: "Failed to parse JSON from file", "{": "Failed to parse JSON string"} {
+			sources, _, err := collectBatchSources([]string{input})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := sources[0].load(); err == nil || !strings.HasPrefix(err.Error(), want) {
+				t.Errorf("load(%q) error = %v, want %q", input, err, want)
+			}
+		}
+	})
+}
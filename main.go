@@ -1,244 +1,851 @@
 package main
 
 import (
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/hex"
+	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"log"
-	"math/big"
 	"os"
-	"sort"
+	"os/signal"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/lazyton/jackpot-verification/verify"
+)
+
+// Process exit codes
+const (
+	// exitFailed means at least one verification check failed
+	exitFailed = 1
+	// exitAPIError means the API returned an error instead of round data, so
+	// there was nothing to verify
+	exitAPIError = 2
+	// exitUnverifiable means, with --assert-fair, that the round could not be
+	// fully verified: malformed input, a network error or withheld data
+	exitUnverifiable = 3
+	// exitUnparseable means some batch inputs could not be read or decoded,
+	// while every round that could be verified passed
+	exitUnparseable = 4
 )
 
-// VerificationBet represents a bet for verification
-type VerificationBet struct {
-	PlayerAddress string  `json:"player_address"`
-	Amount        float64 `json:"amount"`
-	GiftID        string  `json:"gift_id"`
+// fatalExitCode is the exit code for errors that stop verification; 1
+// unless --assert-fair sets it to exitUnverifiable
+var fatalExitCode = 1
+
+// jsonIndent indents writeJSON output; --compact-json clears it
+var jsonIndent = "  "
+
+// fatalf logs an error that prevents verification and exits
+func fatalf(format string, args ...interface{}) {
+	log.Printf(format, args...)
+	os.Exit(fatalExitCode)
 }
 
-// RoundVerificationData contains all data needed for verification
-type RoundVerificationData struct {
-	Success       bool              `json:"success"`
-	RoundID       string            `json:"round_id"`
-	RoundNumber   int               `json:"round_number"`
-	ServerSeed    string            `json:"server_seed"`
-	ServerHash    string            `json:"server_hash"`
-	ClientSeed    string            `json:"client_seed"`
-	PreviousHash  string            `json:"previous_hash"`
-	Bets          []VerificationBet `json:"bets"`
-	Result        float64           `json:"result"`
-	WinnerAddress string            `json:"winner_address"`
-	TotalPot      float64           `json:"total_pot"`
-	Error         string            `json:"error,omitempty"`
+func usage() {
+	fmt.Println("Usage: go run verify_jackpot_round.go [flags] <verification_data.json>")
+	fmt.Println("OR: go run verify_jackpot_round.go [flags] '<json_string>'")
+	fmt.Println("OR: go run verify_jackpot_round.go [flags] --fetch <round_id>")
+	fmt.Println("\nWith --fetch the round is POSTed to /api/jackpot/verify on --api-url (default $" + apiURLEnv + ")")
+	fmt.Println(`and verified in one step. To fetch it yourself, POST {"round_id": "your_round_id"} there.`)
+	fmt.Println("\nFlags:")
+	flag.CommandLine.SetOutput(os.Stdout)
+	flag.PrintDefaults()
 }
 
 func main() {
-	if len(os.Args) < 2 {
-		fmt.Println("Usage: go run verify_jackpot_round.go <verification_data.json>")
-		fmt.Println("OR: go run verify_jackpot_round.go '<json_string>'")
-		fmt.Println("\nTo get verification data, make a POST request to /api/jackpot/verify with:")
-		fmt.Println(`{"round_id": "your_round_id"}`)
-		os.Exit(1)
+	assertFair := flag.Bool("assert-fair", false, "exit 0 only if provably fair, 1 if a check failed, 3 if the input could not be (fully) verified")
+	showVersion := flag.Bool("version", false, "print the verifier version and build info and exit")
+	traceEnabled := flag.Bool("trace", false, "dump every intermediate value of the hash computations in order")
+	gameName := flag.String("game", verify.DefaultGameName, "game profile to verify against (see --list-games)")
+	gamesFile := flag.String("games-file", "", "JSON file with additional game profiles")
+	emitRef := flag.String("emit-reference", "", "print a standalone reference implementation of the algorithm for the selected game in `lang` (python or javascript) and exit")
+	printAlgo := flag.Bool("print-algorithm", false, "describe the exact verification algorithm for the selected game and exit")
+	listGames := flag.Bool("list-games", false, "list available game profiles and exit")
+	tieBreak := flag.Bool("tie-break", false, "resolve results landing exactly on a range boundary with a secondary HMAC draw")
+	clientSeedMode := flag.String("client-seed-mode", "", "client seed derivation: sha256 (default) or hmac of the bets keyed by --client-seed-key")
+	clientSeedKey := flag.String("client-seed-key", "", "HMAC key, typically the public game id, for --client-seed-mode hmac")
+	betOrder := flag.String("bet-order", "", "order bets are hashed in for the client seed: sorted (default, by address) or insertion (as listed)")
+	uniqueGifts := flag.Bool("unique-gifts", false, "fail rounds where a gift id appears in more than one bet (bets without a gift id are exempt)")
+	minWinningBet := flag.Float64("min-winning-bet", 0, "exclude bets below this amount from the winner ranges; they still count toward the pot (0 = every bet can win)")
+	rangeOrder := flag.String("range-order", "", "order winner ranges are assigned in, independently of --bet-order: sorted (default, by address) or insertion (as listed)")
+	house := flag.String("house", "", "comma-separated house addresses whose bets are labeled as the operator's own")
+	winnerRule := flag.String("winner-rule", "", "winner rule: range (the player whose range contains the result) or nearest (the player whose range midpoint is closest to it)")
+	rounding := flag.String("rounding", "", "how results are brought to three decimals before comparison: round (default), truncate or ceil")
+	amountsAreShares := flag.Bool("amounts-are-shares", false, "treat bet amounts as pre-computed percentage shares that must sum to 100 and are used directly as ranges")
+	fallbackRule := flag.String("fallback-rule", "", "winner for a result outside every range, overriding the round's fallback_rule: last, first or fail")
+	timings := flag.Bool("timings", false, "report the time spent parsing and in each check, and the number of hash operations")
+	showBigInt := flag.Bool("show-bigint", false, "show the HMAC as a big integer (hex and decimal), the modulus and the remainder before division")
+	partial := flag.Bool("partial", false, "skip checks whose input data is withheld instead of failing them")
+	hexCaseInsensitive := flag.Bool("hex-case-insensitive", false, "compare claimed server hash and client seed case-insensitively (hashed bytes are unchanged)")
+	minSeedBits := flag.Float64("min-seed-bits", 0, "fail if the revealed server seed's estimated entropy is below this many bits (e.g. 128)")
+	maxBet := flag.Float64("max-bet", 0, "fail if any single bet exceeds this amount in TON (bets are always checked against the total pot)")
+	expectWinner := flag.String("expect-winner", "", "additionally fail unless the recomputed winner is this address")
+	redact := flag.Bool("redact", false, "replace player addresses with stable pseudonyms in all output")
+	verbose := flag.Bool("verbose", false, "show additional detail, such as the --redact pseudonym mapping")
+	noColor := flag.Bool("no-color", false, "disable colored hash diffs (also disabled when NO_COLOR is set or stdout is not a terminal)")
+	oneline := flag.Bool("oneline", false, "print a single key=value line per round with no emoji or banners")
+	emitCanonical := flag.Bool("emit-canonical", false, "print each result as one canonical JSON line (sorted keys, fixed precision, no version) for diffing verifier builds")
+	jsonOutput := flag.Bool("json", false, "print the verification report as JSON")
+	compactJSON := flag.Bool("compact-json", false, "like --json, but print each document on a single line without whitespace")
+	outNDJSON := flag.Bool("out-ndjson", false, "in batch mode, stream one JSON result per line as each round completes")
+	workers := flag.Int("workers", 1, "number of rounds to verify concurrently in batch mode")
+	chain := flag.Bool("chain", false, "in batch mode, also verify the rounds form an unbroken chain")
+	auditLogFile := flag.String("audit-log", "", "append every comparison made (check, expected and actual values, outcome) with a timestamp and the verifier version to this NDJSON file")
+	checkpointFile := flag.String("checkpoint", "", "in batch mode, record completed rounds in this file and skip rounds it already holds")
+	outputDir := flag.String("output-dir", "", "in batch mode, also write each round's report to its own file in this directory, named by round id, in the selected format")
+	inputKind := flag.String("input", InputAuto, "how to read the input argument: auto (a file path, else inline JSON), file, inline, stdin (no argument) or url")
+	scan := flag.Bool("scan", false, "count rounds, bets, total pot and dates of the inputs without verifying them")
+	payouts := flag.Bool("payouts", false, "in batch mode, also audit that each winner was paid the pot minus --house-edge and the session totals add up")
+	baselineFile := flag.String("baseline", "", "in batch mode, also report rounds whose computed result or winner differs from this file of expected outcomes")
+	houseEdge := flag.Float64("house-edge", 0, "percentage of each pot the house keeps, for --payouts")
+	resultStatsFlag := flag.Bool("result-stats", false, "in batch mode, also report the distribution of results and flag over-represented round numbers such as 50.000 (never fails verification)")
+	fairness := flag.Bool("fairness", false, "in batch mode, also report the share of clean rounds as a 0-100 fairness score")
+	progressive := flag.Bool("progressive", false, "verify progressive pot rollover across the input rounds")
+	fetchRoundID := flag.String("fetch", "", "fetch the round with this id from the API and verify it")
+	auditDay := flag.String("audit-day", "", "fetch every round played on this UTC `date` (e.g. 2024-01-15) from the API, verify each and the chain between them")
+	apiURL := flag.String("api-url", defaultAPIBaseURL(), "API base URL for --fetch and --audit-day (default from $"+apiURLEnv+")")
+	fetchTimeout := flag.Duration("timeout", 30*time.Second, "overall timeout for --fetch requests")
+	previewCommit := flag.String("preview-commit", "", "validate and timestamp a next-round server hash commitment, then exit")
+	whatIf := flag.String("what-if", "", "show who would win if the result were this value instead of the claimed one")
+	detectFormulaFlag := flag.Bool("detect-formula", false, "verify the round under every registered game profile and report which reproduce its result and winner")
+	detectModulusFlag := flag.Bool("detect-modulus", false, "redraw the result under the game's modulus and nearby ones (100000, 100001, 10000, 10001) and report which reproduces the claimed result")
+	checkTxs := flag.Bool("check-txs", false, "confirm on chain that each bet's tx_hash transferred at least its amount from the player to --operator-wallet")
+	tonAPI := flag.String("ton-api", defaultTONAPIURL, "toncenter v3 API base URL for --check-txs (API key from $TON_API_KEY)")
+	operatorWallet := flag.String("operator-wallet", "", "operator wallet address bets must be paid to, for --check-txs")
+	compareSeedScheme := flag.String("compare-seed-scheme", "", "derive the client seed under the selected game's scheme and this alternate `scheme` (e.g. insertion, hmac, insertion-hmac) and report whether they match")
+	grinding := flag.Bool("grinding", false, "redraw the round without each bet in turn and flag operator bets that changed the winner")
+	commitURL := flag.String("commit-url", "", "fetch the pre-round server hash commitment from this URL (http(s):// or ipfs://) and use it in check #1 instead of the round's server_hash")
+	drand := flag.String("drand", "", "comma-separated drand relay URLs (e.g. "+defaultDrandURL+") to fetch the round's beacon_round from and check its beacon_value against")
+	mirrors := flag.String("mirror", "", "comma-separated API mirror URLs that must return the same data as --api-url for --fetch")
+	player := flag.String("player", "", "player address for --check-my-range")
+	checkMyRange := flag.Bool("check-my-range", false, "verify only that --player's bets were counted, their range is correct and whether the result fell in it")
+	receiptFile := flag.String("receipt", "", "verify that the bet in this signed receipt (file or inline JSON) was included in the round")
+	receiptKey := flag.String("receipt-key", "", "hex ed25519 operator public key that signs bet receipts, for --receipt")
+	operators := flag.String("operator", "", "comma-separated operator addresses for --grinding")
+	rangesJSON := flag.String("ranges-json", "", "also write the computed winner ranges as JSON to this file, whatever the output mode")
+	share := flag.Bool("share", false, "print a shareable proof link for the verified round")
+	proofLink := flag.String("proof", "", "check a shared proof link against the round (fetched from the link's API when no input is given)")
+	serveAddr := flag.String("serve", "", "serve POST /verify on this address (e.g. :8080) instead of verifying an input")
+	safeErrors := flag.Bool("safe-errors", false, "with --serve, return only the failed check names, never expected/actual values or parse details")
+	maxRequestSize := flag.Int64("max-request-bytes", maxRequestBytes, "with --serve, reject request bodies larger than this many bytes (0 = no limit)")
+	maxBets := flag.Int("max-bets", 0, "with --serve, reject rounds with more than this many bets (0 = no limit)")
+	verifyTimeout := flag.Duration("verify-timeout", 0, "with --serve, abort a verification that runs longer than this (e.g. 5s; 0 = no limit)")
+	watch := flag.Bool("watch", false, "re-verify the input file every time it changes, until interrupted")
+	rotation := flag.Bool("rotation", false, "treat the input as a seed rotation record (old chain tip + new genesis)")
+	rotationKey := flag.String("rotation-key", "", "trusted hex ed25519 operator key for --rotation; without it the record's own key is used and the rotation is reported as unpinned (exit 3)")
+	flag.Usage = usage
+	flag.Parse()
+
+	useColor = !*noColor && colorSupported()
+	if *assertFair {
+		fatalExitCode = exitUnverifiable
+	}
+	if *compactJSON {
+		*jsonOutput = true
+		jsonIndent = ""
 	}
 
-	var data RoundVerificationData
-	input := os.Args[1]
+	if *showVersion {
+		printVersion()
+		return
+	}
 
-	// Try to read as file first
-	if fileData, err := os.ReadFile(input); err == nil {
-		if err := json.Unmarshal(fileData, &data); err != nil {
-			log.Fatalf("Failed to parse JSON from file: %v", err)
+	if *gamesFile != "" {
+		if err := loadGamesFile(*gamesFile); err != nil {
+			fatalf("Failed to load games file: %v", err)
 		}
-	} else {
-		// Try to parse as JSON string
-		if err := json.Unmarshal([]byte(input), &data); err != nil {
-			log.Fatalf("Failed to parse JSON string: %v", err)
+	}
+	if *listGames {
+		printGames()
+		return
+	}
+	game, err := verify.LookupGame(*gameName)
+	if err != nil {
+		fatalf("%v", err)
+	}
+
+	if *previewCommit != "" {
+		record, err := recordCommitment(game, *previewCommit, time.Now())
+		if err != nil {
+			fatalf("Invalid commitment: %v", err)
 		}
+		if *jsonOutput {
+			writeJSON(record)
+		} else {
+			printCommitmentRecord(record)
+		}
+		return
 	}
 
-	if !data.Success {
-		log.Fatalf("Verification data contains error: %s", data.Error)
+	switch *inputKind {
+	case InputAuto, InputFile, InputInline, InputURL:
+		if *inputKind != InputAuto && flag.NArg() > 1 {
+			fatalf("--input %s reads a single round; pass one argument", *inputKind)
+		}
+	case InputStdin:
+		if flag.NArg() > 0 {
+			fatalf("--input stdin reads the round from standard input and takes no argument")
+		}
+	default:
+		fatalf("Invalid --input %q (want %s, %s, %s, %s or %s)", *inputKind, InputAuto, InputFile, InputInline, InputStdin, InputURL)
 	}
 
-	fmt.Printf("🎰 Verifying Jackpot Round #%d (%s)\n", data.RoundNumber, data.RoundID)
-	fmt.Printf("📊 Total Pot: %.2f TON\n", data.TotalPot)
-	fmt.Printf("🎯 Claimed Result: %.3f\n", data.Result)
-	fmt.Printf("🏆 Claimed Winner: %s\n", data.WinnerAddress)
-	fmt.Println(strings.Repeat("=", 60))
+	if flag.NArg() < 1 && *fetchRoundID == "" && *auditDay == "" && *proofLink == "" && *serveAddr == "" && !*printAlgo && *emitRef == "" && *inputKind != InputStdin {
+		usage()
+		os.Exit(fatalExitCode)
+	}
+	switch *fallbackRule {
+	case "", verify.FallbackLast, verify.FallbackFirst, verify.FallbackFail:
+	default:
+		fatalf("Invalid --fallback-rule %q (want %s, %s or %s)", *fallbackRule, verify.FallbackLast, verify.FallbackFirst, verify.FallbackFail)
+	}
+	if *receiptFile != "" && *receiptKey == "" {
+		fatalf("--receipt needs --receipt-key: a receipt proves nothing without the operator's key")
+	}
+	if *checkMyRange && *player == "" {
+		fatalf("--check-my-range needs --player <address>")
+	}
+	if *mirrors != "" && *fetchRoundID == "" && *proofLink == "" {
+		fatalf("--mirror needs --fetch: only fetched rounds can be cross-checked")
+	}
+	if *auditDay != "" {
+		day, err := parseAuditDay(*auditDay)
+		if err != nil {
+			fatalf("%v", err)
+		}
+		*auditDay = day
+		if *fetchRoundID != "" || *proofLink != "" || flag.NArg() > 0 {
+			fatalf("--audit-day fetches its rounds from the API and takes no --fetch, --proof or input argument")
+		}
+	}
+	if *houseEdge < 0 || *houseEdge >= 100 {
+		fatalf("Invalid --house-edge %g: must be a percentage in [0, 100)", *houseEdge)
+	}
+	if *maxRequestSize < 0 || *maxBets < 0 || *verifyTimeout < 0 {
+		fatalf("--max-request-bytes, --max-bets and --verify-timeout cannot be negative")
+	}
+	if *checkTxs && *operatorWallet == "" {
+		fatalf("--check-txs needs --operator-wallet: a payment only counts if it reached the operator")
+	}
+	if *share && *redact {
+		fatalf("--share cannot be combined with --redact: the proof names the real winner")
+	}
+	if *auditLogFile != "" && *redact {
+		fatalf("--audit-log cannot be combined with --redact: the log records the values actually compared")
+	}
+	var audit *auditLog
+	if *auditLogFile != "" {
+		if audit, err = openAuditLog(*auditLogFile); err != nil {
+			fatalf("Failed to open audit log: %v", err)
+		}
+		defer audit.Close()
+	}
 
-	passed := true
+	if *tieBreak {
+		game.TieBreak = true
+	}
+	if *clientSeedMode != "" {
+		game.ClientSeedMode = *clientSeedMode
+	}
+	if *clientSeedKey != "" {
+		game.ClientSeedKey = *clientSeedKey
+	}
+	if *betOrder != "" {
+		game.BetOrder = *betOrder
+	}
+	if *rangeOrder != "" {
+		game.RangeOrder = *rangeOrder
+	}
+	if *minWinningBet != 0 {
+		game.MinWinningBet = *minWinningBet
+	}
+	if *uniqueGifts {
+		game.UniqueGifts = true
+	}
+	if *winnerRule != "" {
+		game.WinnerRule = *winnerRule
+	}
+	if *house != "" {
+		game.HouseAddresses = splitList(*house)
+	}
+	if *rounding != "" {
+		game.ResultRounding = *rounding
+	}
+	if err := game.Validate(); err != nil {
+		fatalf("%v", err)
+	}
 
-	fmt.Println("1️⃣  Verifying Server Hash...")
-	expectedHash := hashString(data.ServerSeed)
-	if expectedHash == data.ServerHash {
-		fmt.Printf("    ✅ Server hash matches: %s\n", data.ServerHash[:16]+"...")
-	} else {
-		fmt.Printf("    ❌ Server hash mismatch!\n")
-		fmt.Printf("       Expected: %s\n", expectedHash)
-		fmt.Printf("       Got:      %s\n", data.ServerHash)
-		passed = false
+	if *printAlgo {
+		printAlgorithm(game)
+		return
+	}
+	if *emitRef != "" {
+		if err := emitReference(*emitRef, game); err != nil {
+			fatalf("%v", err)
+		}
+		return
 	}
 
-	fmt.Println("2️⃣  Verifying Client Seed...")
-	calculatedClientSeed := generateClientSeed(data.Bets)
-	if calculatedClientSeed == data.ClientSeed {
-		fmt.Printf("    ✅ Client seed matches: %s\n", data.ClientSeed[:16]+"...")
-	} else {
-		fmt.Printf("    ❌ Client seed mismatch!\n")
-		fmt.Printf("       Calculated: %s\n", calculatedClientSeed)
-		fmt.Printf("       Claimed:    %s\n", data.ClientSeed)
-		passed = false
+	opts := verify.Options{
+		Game:               game,
+		Trace:              *traceEnabled,
+		HexCaseInsensitive: *hexCaseInsensitive,
+		ExpectWinner:       *expectWinner,
+		MinSeedBits:        *minSeedBits,
+		Partial:            *partial,
+		MaxBet:             *maxBet,
+		AmountsAreShares:   *amountsAreShares,
+		ShowBigInt:         *showBigInt,
+		Timings:            *timings,
+		FallbackRule:       *fallbackRule,
 	}
 
-	fmt.Println("3️⃣  Verifying Result Calculation...")
-	calculatedResult := calculateResult(data.ServerSeed, data.ClientSeed, data.RoundNumber, data.PreviousHash)
-	if fmt.Sprintf("%.3f", calculatedResult) == fmt.Sprintf("%.3f", data.Result) {
-		fmt.Printf("    ✅ Result matches: %.3f\n", data.Result)
-	} else {
-		fmt.Printf("    ❌ Result mismatch!\n")
-		fmt.Printf("       Calculated: %.3f\n", calculatedResult)
-		fmt.Printf("       Claimed:    %.3f\n", data.Result)
-		passed = false
+	if *serveAddr != "" {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		limits := serveLimits{MaxBytes: *maxRequestSize, MaxBets: *maxBets, Timeout: *verifyTimeout}
+		serveOpts := serveOptions{Addr: *serveAddr, Verify: opts, SafeErrors: *safeErrors, Limits: limits, DrandTimeout: *fetchTimeout}
+		if *drand != "" {
+			serveOpts.Drand = splitList(*drand)
+		}
+		if err := serve(ctx, serveOpts); err != nil {
+			fatalf("Server failed: %v", err)
+		}
+		return
 	}
 
-	fmt.Println("4️⃣  Verifying Winner Selection...")
-	calculatedWinner := selectWinner(data.Bets, data.Result)
-	if calculatedWinner == data.WinnerAddress {
-		fmt.Printf("    ✅ Winner matches: %s\n", data.WinnerAddress)
-	} else {
-		fmt.Printf("    ❌ Winner mismatch!\n")
-		fmt.Printf("       Calculated: %s\n", calculatedWinner)
-		fmt.Printf("       Claimed:    %s\n", data.WinnerAddress)
-		passed = false
+	if *rotation {
+		record, err := loadRotationRecord(flag.Arg(0))
+		if err != nil {
+			fatalf("%v", err)
+		}
+		result := verifyRotation(record, *rotationKey, opts)
+		if *jsonOutput {
+			writeJSON(result)
+		} else {
+			printRotationReport(record, result)
+		}
+		if !result.Passed {
+			os.Exit(result.exitCode())
+		}
+		return
 	}
 
-	fmt.Println("5️⃣  Winner Ranges:")
-	showWinnerRanges(data.Bets, data.Result)
+	if *progressive {
+		sources, _, err := collectBatchSources(flag.Args())
+		if err != nil {
+			fatalf("Failed to list inputs: %v", err)
+		}
+		rounds, err := loadRounds(sources)
+		if err != nil {
+			fatalf("%v", err)
+		}
+		report := verifyProgressive(rounds)
+		if *jsonOutput {
+			writeJSON(report)
+		} else {
+			printProgressiveReport(report)
+		}
+		if !report.Passed {
+			os.Exit(exitFailed)
+		}
+		return
+	}
 
-	fmt.Println(strings.Repeat("=", 60))
-	if passed {
-		fmt.Println("🎉 VERIFICATION PASSED! This round is provably fair.")
-	} else {
-		fmt.Println("💀 VERIFICATION FAILED! This round may not be fair.")
-		os.Exit(1)
+	if *scan {
+		sources, _, err := collectBatchSources(flag.Args())
+		if err != nil {
+			fatalf("Failed to list scan inputs: %v", err)
+		}
+		report := scanSources(sources)
+		if *jsonOutput {
+			writeJSON(report)
+		} else {
+			printScanReport(report)
+		}
+		return
 	}
-}
 
-func hashString(str string) string {
-	h := sha256.Sum256([]byte(str))
-	return hex.EncodeToString(h[:])
-}
+	// Auto input is read and split into rounds once; a single round is
+	// verified below from the same source
+	var sources []batchSource
+	batch := *auditDay != ""
+	if !batch && *inputKind == InputAuto {
+		if sources, batch, err = collectBatchSources(flag.Args()); err != nil {
+			fatalf("Failed to list batch inputs: %v", err)
+		}
+	}
+	if batch {
+		if *commitURL != "" {
+			fatalf("--commit-url applies to a single round, not a batch")
+		}
+		if *auditDay != "" {
+			sources, err = daySources(fetchOptions{APIURL: *apiURL, Timeout: *fetchTimeout}, *auditDay)
+			if err != nil {
+				fatalf("%v", err)
+			}
+		}
+		batch := batchOptions{Workers: *workers, Audit: audit, DrandTimeout: *fetchTimeout}
+		if *drand != "" {
+			batch.Drand = splitList(*drand)
+		}
+		if *checkpointFile != "" {
+			cp, err := openCheckpoint(*checkpointFile, opts)
+			if err != nil {
+				fatalf("Failed to open checkpoint: %v", err)
+			}
+			defer cp.Close()
+			batch.Checkpoint = cp
+		}
+		var ndjson *ndjsonWriter
+		if *outNDJSON {
+			ndjson = newNDJSONWriter(os.Stdout)
+			batch.OnRound = streamRounds(ndjson, *redact)
+		}
+		var baseline map[string]BaselineEntry
+		if *baselineFile != "" {
+			if baseline, err = loadBaseline(*baselineFile); err != nil {
+				fatalf("%v", err)
+			}
+		}
+		report, err := runBatch(sources, opts, batch)
+		if err != nil {
+			fatalf("Batch aborted: %v", err)
+		}
+		report.Date = *auditDay
+		if *chain || *auditDay != "" {
+			chain := verify.CheckChainLinks(report.loadedRounds(), report.loadedResults())
+			report.Chain = &chain
+		}
+		if *fairness {
+			report.Fairness = scoreFairness(report)
+		}
+		if *payouts {
+			report.Payouts = verifyPayouts(report.loadedRounds(), *houseEdge)
+		}
+		if baseline != nil {
+			report.Baseline = compareBaseline(report, baseline)
+		}
+		if *resultStatsFlag {
+			report.ResultStats = resultStats(report.loadedRounds(), game)
+		}
+		if *redact {
+			report.redact()
+		}
+		if *outputDir != "" {
+			format := reportFormatText
+			if *emitCanonical {
+				format = reportFormatCanonical
+			} else if *jsonOutput || *outNDJSON {
+				format = reportFormatJSON
+			}
+			if err := writeRoundReports(*outputDir, format, report, opts, *redact); err != nil {
+				fatalf("Failed to write round reports: %v", err)
+			}
+		}
+		if ndjson != nil {
+			ndjson.Write(struct {
+				Summary  BatchSummary        `json:"summary"`
+				Failures []BatchFailure      `json:"failures"`
+				Chain    *verify.ChainReport `json:"chain,omitempty"`
+				Fairness *FairnessReport     `json:"fairness,omitempty"`
+				Payouts  *PayoutReport       `json:"payouts,omitempty"`
+				Baseline *BaselineReport     `json:"baseline,omitempty"`
+				Stats    *ResultStats        `json:"result_stats,omitempty"`
+			}{report.Summary, report.Failures, report.Chain, report.Fairness, report.Payouts, report.Baseline, report.ResultStats})
+		} else if *emitCanonical {
+			for _, round := range report.Rounds {
+				writeCanonical(round)
+			}
+		} else if *jsonOutput {
+			writeJSON(report)
+		} else if *oneline {
+			printBatchOneline(report)
+		} else {
+			printBatchReport(report)
+		}
+		if report.Summary.Failed > 0 || (report.Chain != nil && !report.Chain.Passed) || (report.Payouts != nil && !report.Payouts.Passed) ||
+			(report.Baseline != nil && !report.Baseline.Passed) {
+			os.Exit(exitFailed)
+		}
+		if *assertFair && (report.Summary.Skipped > 0 || report.Summary.Unparseable > 0 || batchPartial(report)) {
+			os.Exit(exitUnverifiable)
+		}
+		if report.Summary.Unparseable > 0 {
+			os.Exit(exitUnparseable)
+		}
+		return
+	}
 
-func generateClientSeed(bets []VerificationBet) string {
-	// Sort bets by player address alphabetically
-	sortedBets := make([]VerificationBet, len(bets))
-	copy(sortedBets, bets)
-	sort.Slice(sortedBets, func(i, j int) bool {
-		return sortedBets[i].PlayerAddress < sortedBets[j].PlayerAddress
-	})
+	if *chain {
+		log.Printf("Note: --chain needs several rounds; a single round has no previous round to link to, so only the round itself is verified")
+	}
 
-	h := sha256.New()
-	for _, bet := range sortedBets {
-		h.Write([]byte(bet.PlayerAddress))
-		h.Write([]byte(fmt.Sprintf("%.3f", bet.Amount)))
-		h.Write([]byte(bet.GiftID))
+	// parseTime is how long the single round took to load, for --timings
+	var parseTime time.Duration
+	// source names the single round's input in the audit log
+	var source string
+
+	// verifyAndPrint verifies a single round in the selected output mode and
+	// returns the result
+	verifyAndPrint := func(data verify.RoundVerificationData) *verify.Report {
+		result := verify.VerifyRoundWithOptions(data, opts)
+		if result.Timings != nil {
+			result.Timings.ParseMS = verify.DurationMS(parseTime)
+		}
+		if audit != nil {
+			if err := audit.record(source, data, result); err != nil {
+				fatalf("%v", err)
+			}
+		}
+		if *share {
+			result.ShareURL = newProof(data, result).URL(*apiURL)
+		}
+		var redactor *Redactor
+		if *redact {
+			redactor = newRedactor(data.Bets)
+			data, result = redactor.Round(data), redactor.Result(result)
+		}
+		if *rangesJSON != "" {
+			ranges, err := data.WinnerRanges()
+			if err != nil {
+				fatalf("Cannot write --ranges-json: %v", err)
+			}
+			if err := writeJSONFile(*rangesJSON, ranges); err != nil {
+				fatalf("Failed to write ranges: %v", err)
+			}
+		}
+		if *emitCanonical {
+			writeCanonical(result)
+		} else if *jsonOutput {
+			writeJSON(result)
+		} else if *oneline {
+			fmt.Println(formatOneline(result))
+		} else {
+			printReport(os.Stdout, data, result)
+			if redactor != nil && *verbose {
+				printRedactionMap(redactor)
+			}
+		}
+		return result
 	}
 
-	return hex.EncodeToString(h.Sum(nil))
-}
+	if *watch {
+		if *fetchRoundID != "" || flag.NArg() != 1 {
+			fatalf("--watch needs a single round file")
+		}
+		path := cleanInputPath(flag.Arg(0))
+		source = path
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		watchFile(ctx, path, func() {
+			printWatchHeader(path)
+			data, err := loadRound(path)
+			switch {
+			case err != nil:
+				fmt.Printf("❌ %v\n", err)
+			case !data.Success:
+				reportAPIError(data, *jsonOutput)
+			default:
+				verifyAndPrint(opts.Apply(data))
+			}
+		})
+		fmt.Println()
+		return
+	}
 
-func calculateResult(serverSeed, clientSeed string, roundNumber int, previousHash string) float64 {
-	combined := fmt.Sprintf("%s:%s:%d:%s", serverSeed, clientSeed, roundNumber, previousHash)
-	h := hmac.New(sha256.New, []byte(serverSeed))
-	h.Write([]byte(combined))
-	hash := h.Sum(nil)
+	var proof Proof
+	if *proofLink != "" {
+		var base string
+		if proof, base, err = parseProof(*proofLink); err != nil {
+			fatalf("Invalid proof link: %v", err)
+		}
+		if flag.NArg() == 0 && *inputKind != InputStdin {
+			if *apiURL, err = proofAPIURL(base, *apiURL, flagSet("api-url")); err != nil {
+				fatalf("%v", err)
+			}
+			*fetchRoundID = proof.RoundID
+		}
+	}
 
-	hashInt := new(big.Int).SetBytes(hash)
-	maxValue := big.NewInt(100001)
-	resultInt := new(big.Int).Mod(hashInt, maxValue)
-	return float64(resultInt.Int64()) / 1000.0
-}
+	var data verify.RoundVerificationData
+	if *fetchRoundID != "" {
+		fetch := fetchOptions{APIURL: *apiURL, Timeout: *fetchTimeout}
+		var stats FetchStats
+		data, stats, err = fetchRound(context.Background(), fetch, *fetchRoundID)
+		if err != nil {
+			fatalf("Failed to fetch round %s from %s: %v", *fetchRoundID, stats.URL, err)
+		}
+		if data.Success && !*jsonOutput && !*oneline {
+			fmt.Printf("📡 Fetched %d bets across %d page(s) from %s\n", stats.Bets, stats.Pages, stats.URL)
+		}
+		if urls := splitList(*mirrors); len(urls) > 0 {
+			divergences, err := fetchMirrors(context.Background(), fetch, urls, *fetchRoundID, data)
+			if err != nil {
+				fatalf("Failed to cross-check mirrors: %v", err)
+			}
+			if *jsonOutput {
+				if len(divergences) > 0 {
+					writeJSON(struct {
+						RoundID     string             `json:"round_id"`
+						Divergences []MirrorDivergence `json:"mirror_divergences"`
+					}{*fetchRoundID, divergences})
+				}
+			} else if !*oneline || len(divergences) > 0 {
+				printMirrorReport(stats.URL, len(urls), divergences)
+			}
+			if len(divergences) > 0 {
+				os.Exit(exitFailed)
+			}
+		}
+	} else {
+		began := time.Now()
+		if len(sources) == 1 {
+			data, err = sources[0].load()
+		} else {
+			data, err = loadRoundAs(*inputKind, flag.Arg(0), *fetchTimeout)
+		}
+		if err != nil {
+			fatalf("%v", err)
+		}
+		parseTime = time.Since(began)
+		source = auditSource(*inputKind, flag.Arg(0))
+	}
 
-func selectWinner(bets []VerificationBet, result float64) string {
-	if len(bets) == 0 {
-		return ""
+	if !data.Success {
+		reportAPIError(data, *jsonOutput)
+		if *assertFair {
+			os.Exit(exitUnverifiable)
+		}
+		os.Exit(exitAPIError)
+	}
+	data = opts.Apply(data)
+	if *commitURL != "" {
+		commitment, err := fetchCommitment(context.Background(), *commitURL, *fetchTimeout)
+		if err != nil {
+			fatalf("Failed to fetch commitment from %s: %v", *commitURL, err)
+		}
+		opts.Commitment = commitment
+	}
+	if *drand != "" && data.BeaconRound != 0 {
+		beacons, err := fetchDrandBeacons(context.Background(), splitList(*drand), data.BeaconRound, *fetchTimeout)
+		if err != nil {
+			fatalf("Failed to fetch drand beacon round %d: %v", data.BeaconRound, err)
+		}
+		opts.Beacons = beacons
 	}
 
-	// Sort bets by player address alphabetically
-	sortedBets := make([]VerificationBet, len(bets))
-	copy(sortedBets, bets)
-	sort.Slice(sortedBets, func(i, j int) bool {
-		return sortedBets[i].PlayerAddress < sortedBets[j].PlayerAddress
-	})
+	if *whatIf != "" {
+		hypothetical, err := strconv.ParseFloat(*whatIf, 64)
+		if err != nil {
+			fatalf("Invalid --what-if result %q: %v", *whatIf, err)
+		}
+		display := data
+		if *redact {
+			redactor := newRedactor(data.Bets)
+			display = redactor.Round(data)
+			defer func() {
+				if *verbose {
+					printRedactionMap(redactor)
+				}
+			}()
+		}
+		printWhatIf(game, display, hypothetical)
+		return
+	}
 
-	// Calculate total bet amount
-	totalBets := 0.0
-	for _, bet := range sortedBets {
-		totalBets += bet.Amount
+	if *detectFormulaFlag {
+		report := detectFormula(data, opts)
+		if *jsonOutput {
+			writeJSON(report)
+		} else {
+			printFormulaReport(report)
+		}
+		if len(report.Matching) == 0 {
+			os.Exit(exitFailed)
+		}
+		return
 	}
 
-	// Find winner based on result position
-	currentPosition := 0.0
-	for _, bet := range sortedBets {
-		betPercentage := (bet.Amount / totalBets) * 100.0
-		rangeEnd := currentPosition + betPercentage
+	if *detectModulusFlag {
+		report := detectModulus(data, game)
+		if *jsonOutput {
+			writeJSON(report)
+		} else {
+			printModulusReport(report)
+		}
+		if len(report.Matching) == 0 {
+			os.Exit(exitFailed)
+		}
+		return
+	}
 
-		if result >= currentPosition && result < rangeEnd {
-			return bet.PlayerAddress
+	if *compareSeedScheme != "" {
+		alternate, err := applySeedScheme(game, *compareSeedScheme)
+		if err != nil {
+			fatalf("Invalid --compare-seed-scheme: %v", err)
 		}
+		comparison := compareSeedSchemes(data, opts, alternate)
+		if *jsonOutput {
+			writeJSON(comparison)
+		} else {
+			printSeedSchemeComparison(comparison)
+		}
+		if !comparison.Current.MatchesClaimed && !comparison.Alternate.MatchesClaimed {
+			os.Exit(exitFailed)
+		}
+		return
+	}
 
-		currentPosition = rangeEnd
+	if *checkTxs {
+		report := checkBetTransactions(context.Background(), data, txOptions{
+			APIURL:         *tonAPI,
+			OperatorWallet: *operatorWallet,
+			Timeout:        *fetchTimeout,
+		})
+		if *jsonOutput {
+			writeJSON(report)
+		} else {
+			printTransactionReport(report)
+		}
+		if !report.Passed {
+			os.Exit(exitFailed)
+		}
+		return
 	}
 
-	// Should never reach here if bets are valid
-	return sortedBets[len(sortedBets)-1].PlayerAddress
-}
+	if *receiptFile != "" {
+		receipt, err := loadBetReceipt(*receiptFile)
+		if err != nil {
+			fatalf("%v", err)
+		}
+		checks := verifyReceipt(receipt, *receiptKey, data, opts)
+		passed := true
+		for _, check := range checks {
+			passed = passed && check.Passed
+		}
+		if *jsonOutput {
+			writeJSON(struct {
+				Receipt BetReceipt     `json:"receipt"`
+				Passed  bool           `json:"passed"`
+				Checks  []verify.Check `json:"checks"`
+			}{receipt, passed, checks})
+		} else {
+			printReceiptChecks(receipt, checks)
+		}
+		if !passed {
+			os.Exit(exitFailed)
+		}
+		return
+	}
 
-func showWinnerRanges(bets []VerificationBet, result float64) {
-	if len(bets) == 0 {
-		fmt.Println("    No bets to show")
+	if *checkMyRange {
+		report := checkPlayerRange(data, opts, *player)
+		if *jsonOutput {
+			writeJSON(report)
+		} else {
+			printPlayerRange(report)
+		}
+		if !report.Passed {
+			os.Exit(exitFailed)
+		}
 		return
 	}
 
-	// Sort bets by player address alphabetically
-	sortedBets := make([]VerificationBet, len(bets))
-	copy(sortedBets, bets)
-	sort.Slice(sortedBets, func(i, j int) bool {
-		return sortedBets[i].PlayerAddress < sortedBets[j].PlayerAddress
-	})
+	if *grinding {
+		report := detectGrinding(data, game, splitList(*operators))
+		if *redact {
+			report.redact(newRedactor(data.Bets))
+		}
+		if *jsonOutput {
+			writeJSON(report)
+		} else {
+			printGrindingReport(report)
+		}
+		if report.Flagged {
+			os.Exit(exitFailed)
+		}
+		return
+	}
 
-	// Calculate total bet amount
-	totalBets := 0.0
-	for _, bet := range sortedBets {
-		totalBets += bet.Amount
+	if *proofLink != "" {
+		checks := checkProof(proof, data, verify.VerifyRoundWithOptions(data, opts))
+		passed := true
+		for _, check := range checks {
+			passed = passed && check.Passed
+		}
+		if *jsonOutput {
+			writeJSON(struct {
+				Proof  Proof        `json:"proof"`
+				Passed bool         `json:"passed"`
+				Checks []ProofCheck `json:"checks"`
+			}{proof, passed, checks})
+		} else {
+			printProofChecks(proof.RoundID, checks)
+		}
+		if !passed {
+			os.Exit(exitFailed)
+		}
+		return
 	}
 
-	// Show ranges
-	currentPosition := 0.0
-	for _, bet := range sortedBets {
-		betPercentage := (bet.Amount / totalBets) * 100.0
-		rangeEnd := currentPosition + betPercentage
+	result := verifyAndPrint(data)
+	if !result.Passed {
+		os.Exit(exitFailed)
+	}
+	if *assertFair && result.Partial {
+		os.Exit(exitUnverifiable)
+	}
+}
 
-		isWinner := result >= currentPosition && result < rangeEnd
-		winnerIcon := "  "
-		if isWinner {
-			winnerIcon = "🏆"
+// flagSet reports whether the named flag was given on the command line
+func flagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
 		}
+	})
+	return set
+}
 
-		playerDisplay := bet.PlayerAddress
-		if len(playerDisplay) > 8 {
-			playerDisplay = playerDisplay[:4] + "..." + playerDisplay[len(playerDisplay)-4:]
+// splitList splits a comma-separated flag value, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
 		}
+	}
+	return items
+}
 
-		fmt.Printf("    %s %s: %.3f - %.3f (%.1f%% chance, %.2f TON)\n",
-			winnerIcon, playerDisplay, currentPosition, rangeEnd, betPercentage, bet.Amount)
+// writeJSON prints v as JSON on stdout, indented unless --compact-json
+func writeJSON(v interface{}) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", jsonIndent)
+	if err := enc.Encode(v); err != nil {
+		fatalf("Failed to encode JSON: %v", err)
+	}
+}
 
-		currentPosition = rangeEnd
+// writeJSONFile writes v as indented JSON to path
+func writeJSONFile(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
 	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
 
-	fmt.Printf("    🎯 Result %.3f falls in winner's range\n", result)
+// shortAddress abbreviates a player address to its first and last four
+// characters. Addresses too short to abbreviate, including empty ones, are
+// returned unchanged.
+func shortAddress(address string) string {
+	if len(address) > 8 && !isPseudonym(address) {
+		return address[:4] + "..." + address[len(address)-4:]
+	}
+	return address
 }
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lazyton/jackpot-verification/verify"
+)
+
+func TestShortAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		want    string
+	}{
+		{address: "", want: ""},
+		{address: "EQab", want: "EQab"},
+		{address: "EQabcdef", want: "EQabcdef"},
+		{address: "EQabcdefg", want: "EQab...defg"},
+		{address: "EQA1aaaaaaaa4B2C", want: "EQA1...4B2C"},
+		{address: "Player-12345", want: "Player-12345"},
+	}
+	for _, tt := range tests {
+		if got := shortAddress(tt.address); got != tt.want {
+			t.Errorf("shortAddress(%q) = %q, want %q", tt.address, got, tt.want)
+		}
+	}
+}
+
+// TestPrintReportShortValues renders rounds whose hashes and addresses are
+// shorter than the display abbreviates; any out-of-range slice panics
+func TestPrintReportShortValues(t *testing.T) {
+	tests := []struct {
+		name string
+		data verify.RoundVerificationData
+	}{
+		{name: "4-char hashes", data: verify.RoundVerificationData{
+			Success: true, ServerSeed: "abcd", ServerHash: "abcd", ClientSeed: "abcd", PreviousHash: "abcd",
+			Bets:          []verify.VerificationBet{{PlayerAddress: "EQa", Amount: 1}, {PlayerAddress: "EQb", Amount: 2}},
+			WinnerAddress: "EQb", Result: 50,
+		}},
+		{name: "empty addresses", data: verify.RoundVerificationData{
+			Success: true, ServerSeed: "abcd",
+			Bets:   []verify.VerificationBet{{Amount: 1}, {Amount: 2}},
+			Result: 50,
+		}},
+		{name: "everything empty", data: verify.RoundVerificationData{Success: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := verify.VerifyRoundWithOptions(tt.data, verify.Options{Game: verify.GameRegistry[verify.DefaultGameName]})
+			printReport(io.Discard, tt.data, result)
+			showRoundRanges(io.Discard, tt.data, tt.data.Result, "", nil)
+		})
+	}
+}
+
+func TestWriteTextReportLeavesStdout(t *testing.T) {
+	var data verify.RoundVerificationData
+	if err := json.Unmarshal([]byte(prettyRound), &data); err != nil {
+		t.Fatal(err)
+	}
+	data.ServerHash = strings.Repeat("0", 64)
+	result := verify.VerifyRoundWithOptions(data, verify.Options{Game: verify.GameRegistry[verify.DefaultGameName]})
+
+	stdout, color := os.Stdout, useColor
+	useColor = true
+	defer func() { useColor = color }()
+
+	path := filepath.Join(t.TempDir(), "round.txt")
+	if err := writeTextReport(path, data, result); err != nil {
+		t.Fatal(err)
+	}
+	if os.Stdout != stdout || !useColor {
+		t.Error("writeTextReport changed os.Stdout or useColor")
+	}
+	text, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(text), "Server hash mismatch") || !strings.Contains(string(text), "^ first difference") {
+		t.Errorf("report file lacks the hash diff:\n%s", text)
+	}
+	if strings.Contains(string(text), "\033[") {
+		t.Error("report file contains ANSI color codes")
+	}
+}
+
+func TestWriteRoundReportsUsesOptions(t *testing.T) {
+	var data verify.RoundVerificationData
+	if err := json.Unmarshal([]byte(prettyRound), &data); err != nil {
+		t.Fatal(err)
+	}
+	game := verify.GameRegistry[verify.DefaultGameName]
+	game.MinWinningBet = 14
+	opts := verify.Options{Game: game}
+	result := verify.VerifyRoundWithOptions(data, opts)
+	report := &BatchReport{Rounds: []BatchRound{{Source: "round.json", Data: &data, Result: result}}}
+
+	dir := t.TempDir()
+	if err := writeRoundReports(dir, reportFormatText, report, opts, false); err != nil {
+		t.Fatal(err)
+	}
+	text, err := os.ReadFile(filepath.Join(dir, "round-1.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(text), "1 bet(s) below the 14.000 TON minimum winning bet") {
+		t.Errorf("report does not leave the small bet out of the ranges:\n%s", text)
+	}
+	if strings.Contains(string(text), "EQA1...4B2C: 0.000") {
+		t.Errorf("report gives the ineligible bet a range:\n%s", text)
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/lazyton/jackpot-verification/verify"
+)
+
+// MirrorDivergence describes how one mirror's copy of a round differs from
+// the primary API's
+type MirrorDivergence struct {
+	URL         string   `json:"url"`
+	Differences []string `json:"differences"`
+}
+
+// fetchMirrors fetches the round from every mirror and compares each copy
+// with primary. A mirror that cannot be fetched is an error: the round
+// cannot be cross-checked, which is not evidence that the data differs.
+func fetchMirrors(ctx context.Context, opts fetchOptions, mirrors []string, roundID string, primary verify.RoundVerificationData) ([]MirrorDivergence, error) {
+	var divergences []MirrorDivergence
+	for _, url := range mirrors {
+		mirror := opts
+		mirror.APIURL = url
+		data, stats, err := fetchRound(ctx, mirror, roundID)
+		if err != nil {
+			return nil, fmt.Errorf("mirror %s: %v", stats.URL, err)
+		}
+		if differences := diffRounds(primary, data); len(differences) > 0 {
+			divergences = append(divergences, MirrorDivergence{URL: stats.URL, Differences: differences})
+		}
+	}
+	return divergences, nil
+}
+
+// diffRounds lists the fields in which two copies of a round differ, as
+// "field: a vs b". Bets are compared one by one so the first differing bet
+// is named rather than the whole list.
+func diffRounds(a, b verify.RoundVerificationData) []string {
+	fieldsA, fieldsB := jsonFields(a), jsonFields(b)
+	keys := map[string]bool{}
+	for key := range fieldsA {
+		keys[key] = true
+	}
+	for key := range fieldsB {
+		keys[key] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	var differences []string
+	for _, key := range sorted {
+		if key == "bets" {
+			differences = append(differences, diffBets(a.Bets, b.Bets)...)
+			continue
+		}
+		if !bytes.Equal(fieldsA[key], fieldsB[key]) {
+			differences = append(differences, fmt.Sprintf("%s: %s vs %s",
+				key, truncate(string(fieldsA[key]), 80), truncate(string(fieldsB[key]), 80)))
+		}
+	}
+	return differences
+}
+
+// diffBets compares two bet lists position by position
+func diffBets(a, b []verify.VerificationBet) []string {
+	var differences []string
+	if len(a) != len(b) {
+		differences = append(differences, fmt.Sprintf("bets: %d vs %d bets", len(a), len(b)))
+	}
+	for i := 0; i < len(a) && i < len(b); i++ {
+		betA, _ := json.Marshal(a[i])
+		betB, _ := json.Marshal(b[i])
+		if !bytes.Equal(betA, betB) {
+			differences = append(differences, fmt.Sprintf("bets[%d]: %s vs %s", i, betA, betB))
+		}
+	}
+	return differences
+}
+
+// jsonFields encodes a round and splits it into its top-level fields
+func jsonFields(data verify.RoundVerificationData) map[string]json.RawMessage {
+	fields := map[string]json.RawMessage{}
+	raw, err := json.Marshal(data)
+	if err == nil {
+		json.Unmarshal(raw, &fields)
+	}
+	return fields
+}
+
+// printMirrorReport lists how each disagreeing mirror diverged
+func printMirrorReport(primary string, mirrors int, divergences []MirrorDivergence) {
+	if len(divergences) == 0 {
+		fmt.Printf("🪞 %d mirror(s) returned data identical to %s\n", mirrors, primary)
+		return
+	}
+	fmt.Printf("❌ %d of %d mirror(s) disagree with %s — the operator may be serving different data to different auditors\n",
+		len(divergences), mirrors, primary)
+	for _, d := range divergences {
+		fmt.Printf("    🪞 %s\n", d.URL)
+		for _, difference := range d.Differences {
+			fmt.Printf("       %s\n", difference)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lazyton/jackpot-verification/verify"
+)
+
+// PlayerRangeReport is one player's focused view of a round: whether their
+// bets were counted, the ranges they held and whether the result fell in
+// them. Other players' bets are still needed: they position the player's
+// ranges and make up the client seed.
+type PlayerRangeReport struct {
+	RoundID string               `json:"round_id"`
+	Player  string               `json:"player"`
+	Ranges  []verify.WinnerRange `json:"ranges"`
+	// Percent is the player's total chance of winning
+	Percent float64 `json:"percent"`
+	Result  float64 `json:"result"`
+	// InRange reports whether the result fell in one of the player's ranges
+	InRange bool `json:"in_range"`
+	// Ineligible means the player bet, but every bet is below the game's
+	// MinWinningBet, so it counts toward the pot and holds no range
+	Ineligible    bool    `json:"ineligible,omitempty"`
+	MinWinningBet float64 `json:"min_winning_bet,omitempty"`
+	// Won reports whether the recomputed winner is the player, and
+	// DeclaredWinner whether the round declares them the winner
+	Won            bool `json:"won"`
+	DeclaredWinner bool `json:"declared_winner"`
+	// BetCounted means the bet list, the player's bets included, hashes to
+	// the round's client seed; ResultVerified means the result was drawn
+	// from it honestly
+	BetCounted     bool   `json:"bet_counted"`
+	ResultVerified bool   `json:"result_verified"`
+	Passed         bool   `json:"passed"`
+	Error          string `json:"error,omitempty"`
+}
+
+// checkPlayerRange verifies a round from the point of view of one player
+func checkPlayerRange(data verify.RoundVerificationData, opts verify.Options, player string) *PlayerRangeReport {
+	report := &PlayerRangeReport{RoundID: data.RoundID, Player: player, Result: data.Result}
+	data.MinWinningBet = opts.Game.MinWinningBet
+
+	ranges, err := data.WinnerRanges()
+	if err != nil {
+		report.Error = "cannot compute ranges: " + err.Error()
+		return report
+	}
+	for i, r := range ranges {
+		if r.Player != player {
+			continue
+		}
+		report.Ranges = append(report.Ranges, r)
+		report.Percent += r.Percent
+		report.InRange = report.InRange || verify.RangeHolds(ranges, i, data.Result)
+	}
+	if len(report.Ranges) == 0 {
+		if !hasBet(data.Bets, player) {
+			report.Error = fmt.Sprintf("no bet by %s in this round", player)
+			return report
+		}
+		report.Ineligible, report.MinWinningBet = true, data.MinWinningBet
+	}
+
+	result := verify.VerifyRoundWithOptions(data, opts)
+	report.BetCounted = result.CheckPassed(verify.CheckClientSeed)
+	report.ResultVerified = result.CheckPassed(verify.CheckResult)
+	report.Won = result.ComputedWinner == player
+	report.DeclaredWinner = data.WinnerAddress == player
+	report.Passed = report.BetCounted && report.ResultVerified && report.Won == report.DeclaredWinner
+	return report
+}
+
+// hasBet reports whether player placed any of bets
+func hasBet(bets []verify.VerificationBet, player string) bool {
+	for _, bet := range bets {
+		if bet.PlayerAddress == player {
+			return true
+		}
+	}
+	return false
+}
+
+// printPlayerRange prints a player's range report
+func printPlayerRange(report *PlayerRangeReport) {
+	fmt.Printf("🙋 Checking %s in round %s\n", report.Player, report.RoundID)
+	fmt.Println(strings.Repeat("=", 60))
+	if report.Error != "" {
+		fmt.Printf("    ❌ %s\n", report.Error)
+		return
+	}
+
+	if report.BetCounted {
+		fmt.Println("    ✅ Your bets are in the bet list the client seed commits to")
+	} else {
+		fmt.Println("    ❌ The bet list does not hash to the round's client seed; your bets may not have been counted as listed")
+	}
+	if report.Ineligible {
+		fmt.Printf("    🪙 Your bets are below the %.3f TON minimum winning bet: they count toward the pot but hold no range and cannot win\n",
+			report.MinWinningBet)
+	}
+	for _, r := range report.Ranges {
+		fmt.Printf("    📐 Your range: [%.3f, %.3f) — %.3f%% chance for %.2f TON\n", r.Start, r.End, r.Percent, r.Amount)
+	}
+	if len(report.Ranges) > 1 {
+		fmt.Printf("    📊 Total chance: %.3f%%\n", report.Percent)
+	}
+
+	if !report.ResultVerified {
+		fmt.Printf("    ❌ The result %.3f could not be reproduced from the seeds\n", report.Result)
+	} else if report.Ineligible {
+		fmt.Printf("    🎯 Result %.3f was reproduced from the seeds\n", report.Result)
+	} else if report.InRange {
+		fmt.Printf("    🎯 Result %.3f falls in your range\n", report.Result)
+	} else {
+		fmt.Printf("    🎯 Result %.3f is outside your range\n", report.Result)
+	}
+
+	switch {
+	case report.Won && report.DeclaredWinner:
+		fmt.Println("    🏆 You won, and the round declares you the winner")
+	case report.Won:
+		fmt.Println("    ❌ You should have won, but the round declares someone else the winner")
+	case report.DeclaredWinner:
+		fmt.Println("    ❌ The round declares you the winner, but the result does not select you")
+	default:
+		fmt.Println("    ➖ You did not win this round")
+	}
+
+	fmt.Println(strings.Repeat("=", 60))
+	if report.Passed && report.Ineligible {
+		fmt.Println("🎉 Your bets were counted; below the minimum winning bet, they could not win.")
+	} else if report.Passed {
+		fmt.Println("🎉 Your bets were counted and your range was computed correctly.")
+	} else {
+		fmt.Println("💀 Your part of this round does not check out.")
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/lazyton/jackpot-verification/verify"
+)
+
+func TestCheckPlayerRangeMinWinningBet(t *testing.T) {
+	var data verify.RoundVerificationData
+	if err := json.Unmarshal([]byte(prettyRound), &data); err != nil {
+		t.Fatal(err)
+	}
+	game := verify.GameRegistry[verify.DefaultGameName]
+	game.MinWinningBet = 14
+	opts := verify.Options{Game: game}
+
+	tests := []struct {
+		name           string
+		player         string
+		wantError      string
+		wantIneligible bool
+		wantRanges     int
+	}{
+		{name: "bet below the minimum", player: "EQA1aaaaaaaa4B2C", wantIneligible: true},
+		{name: "eligible bet", player: "EQB2bbbbbbbb5C3D", wantRanges: 1},
+		{name: "no bet", player: "EQD4dddddddd7E5F", wantError: "no bet by EQD4dddddddd7E5F in this round"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := checkPlayerRange(data, opts, tt.player)
+			if report.Error != tt.wantError {
+				t.Fatalf("error = %q, want %q", report.Error, tt.wantError)
+			}
+			if report.Ineligible != tt.wantIneligible || len(report.Ranges) != tt.wantRanges {
+				t.Errorf("ineligible = %v with %d ranges, want %v with %d", report.Ineligible, len(report.Ranges), tt.wantIneligible, tt.wantRanges)
+			}
+			if tt.wantIneligible && (!report.BetCounted || !report.Passed || report.MinWinningBet != 14) {
+				t.Errorf("ineligible bet: counted=%v passed=%v minimum %g; want it counted and passing against 14", report.BetCounted, report.Passed, report.MinWinningBet)
+			}
+		})
+	}
+}
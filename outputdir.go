@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lazyton/jackpot-verification/verify"
+)
+
+// Per-round report formats for --output-dir
+const (
+	reportFormatText      = "text"
+	reportFormatJSON      = "json"
+	reportFormatCanonical = "canonical"
+)
+
+// reportExtensions maps each --output-dir format to its file extension
+var reportExtensions = map[string]string{
+	reportFormatText:      ".txt",
+	reportFormatJSON:      ".json",
+	reportFormatCanonical: ".json",
+}
+
+// writeRoundReports writes one report file per verified round into dir,
+// named after the round id. Rounds that could not be loaded have no result
+// and get no file; they are still listed in the batch summary. Text reports
+// draw the ranges under opts, the options the rounds were verified with.
+func writeRoundReports(dir, format string, report *BatchReport, opts verify.Options, redact bool) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	used := map[string]int{}
+	for _, round := range report.Rounds {
+		if round.Result == nil {
+			continue
+		}
+		name := reportFileName(round, used) + reportExtensions[format]
+		path := filepath.Join(dir, name)
+
+		var err error
+		switch format {
+		case reportFormatJSON:
+			err = writeJSONFile(path, round.Result)
+		case reportFormatCanonical:
+			var line []byte
+			if line, err = canonicalJSON(round.Result); err == nil {
+				err = os.WriteFile(path, append(line, '\n'), 0644)
+			}
+		default:
+			data := opts.Apply(*round.Data)
+			if redact {
+				data = newRedactor(data.Bets).Round(data)
+			}
+			err = writeTextReport(path, data, round.Result)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+	}
+	return nil
+}
+
+// writeTextReport writes the human report of a round to path, without
+// color
+func writeTextReport(path string, data verify.RoundVerificationData, result *verify.Report) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	printReport(f, data, result)
+	return f.Close()
+}
+
+// reportFileName derives a file name from the round id (or the round
+// number when there is none), replacing characters that are unsafe in file
+// names and numbering repeats so no report overwrites another
+func reportFileName(round BatchRound, used map[string]int) string {
+	name := round.Result.RoundID
+	if name == "" {
+		name = fmt.Sprintf("round-%d", round.Result.RoundNumber)
+	}
+	name = strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' || r == '*' || r == '?' || r == '"' || r == '<' || r == '>' || r == '|' || r < ' ' {
+			return '_'
+		}
+		return r
+	}, name)
+	if name == "." || name == ".." {
+		name = "_"
+	}
+
+	used[name]++
+	if n := used[name]; n > 1 {
+		name = fmt.Sprintf("%s-%d", name, n)
+	}
+	return name
+}
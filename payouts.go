@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/lazyton/jackpot-verification/verify"
+)
+
+// payoutTolerance absorbs the rounding of a payout and a house cut each
+// settled to three decimal places
+const payoutTolerance = 0.001
+
+// RoundPayout is the accounting of one round's pot: what the winner was paid
+// and what the house kept
+type RoundPayout struct {
+	RoundID        string  `json:"round_id"`
+	RoundNumber    int     `json:"round_number"`
+	Pot            float64 `json:"pot"`
+	Payout         float64 `json:"payout"`
+	ExpectedPayout float64 `json:"expected_payout"`
+	HouseCut       float64 `json:"house_cut"`
+	Passed         bool    `json:"passed"`
+	Problem        string  `json:"problem,omitempty"`
+}
+
+// PayoutReport audits the payouts of a batch: every round's winner received
+// the pot minus the house edge, and the session totals add up
+type PayoutReport struct {
+	Passed bool `json:"passed"`
+	// HouseEdge is the percentage of each pot the house keeps
+	HouseEdge float64       `json:"house_edge"`
+	Rounds    []RoundPayout `json:"rounds"`
+
+	TotalPot      float64 `json:"total_pot"`
+	TotalPaid     float64 `json:"total_paid"`
+	TotalHouseCut float64 `json:"total_house_cut"`
+	// ExpectedHouseCut is the house edge applied to the total pot, and
+	// Discrepancy how far the total paid falls short of (negative: exceeds)
+	// the total pot minus that cut
+	ExpectedHouseCut float64 `json:"expected_house_cut"`
+	Discrepancy      float64 `json:"discrepancy"`
+}
+
+// verifyPayouts checks each round's declared payout and house cut against
+// its pot and houseEdge (a percentage), ordering rounds by round number.
+// Cancelled rounds were refunded and must pay out nothing.
+func verifyPayouts(rounds []verify.RoundVerificationData, houseEdge float64) *PayoutReport {
+	sorted := make([]verify.RoundVerificationData, len(rounds))
+	copy(sorted, rounds)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].RoundNumber < sorted[j].RoundNumber
+	})
+
+	report := &PayoutReport{Passed: true, HouseEdge: houseEdge, Rounds: []RoundPayout{}}
+	for _, data := range sorted {
+		entry := RoundPayout{
+			RoundID:     data.RoundID,
+			RoundNumber: data.RoundNumber,
+			Pot:         data.TotalPot,
+			Payout:      data.Payout,
+			HouseCut:    data.HouseCut,
+		}
+		if data.Cancelled {
+			entry.Pot = 0
+		}
+		entry.ExpectedPayout = entry.Pot * (100 - houseEdge) / 100
+		if entry.HouseCut == 0 {
+			entry.HouseCut = entry.Pot - entry.Payout
+		}
+
+		switch {
+		case data.Cancelled && (data.Payout != 0 || data.HouseCut != 0):
+			entry.Problem = fmt.Sprintf("cancelled round paid out %.3f TON and kept %.3f TON", data.Payout, data.HouseCut)
+		case data.Payout == 0 && entry.Pot > 0:
+			entry.Problem = "no payout declared"
+		case math.Abs(entry.Payout+entry.HouseCut-entry.Pot) > payoutTolerance:
+			entry.Problem = fmt.Sprintf("payout %.3f plus house cut %.3f is not the pot %.3f", entry.Payout, entry.HouseCut, entry.Pot)
+		case math.Abs(entry.Payout-entry.ExpectedPayout) > payoutTolerance:
+			entry.Problem = fmt.Sprintf("payout %.3f is not the pot minus a %g%% house edge, %.3f", entry.Payout, houseEdge, entry.ExpectedPayout)
+		}
+		entry.Passed = entry.Problem == ""
+		if !entry.Passed {
+			report.Passed = false
+		}
+
+		report.TotalPot += entry.Pot
+		report.TotalPaid += entry.Payout
+		report.TotalHouseCut += entry.HouseCut
+		report.Rounds = append(report.Rounds, entry)
+	}
+
+	report.ExpectedHouseCut = report.TotalPot * houseEdge / 100
+	report.Discrepancy = report.TotalPot - report.ExpectedHouseCut - report.TotalPaid
+	if !report.balanced() {
+		report.Passed = false
+	}
+	return report
+}
+
+// balanced reports whether the session totals add up, allowing each round
+// its rounding
+func (r *PayoutReport) balanced() bool {
+	return math.Abs(r.Discrepancy) <= payoutTolerance*float64(len(r.Rounds)+1)
+}
+
+func printPayoutReport(report *PayoutReport) {
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Printf("💸 Payout Audit (%g%% house edge):\n", report.HouseEdge)
+	if len(report.Rounds) == 0 {
+		fmt.Println("    No rounds to audit")
+		return
+	}
+	for _, r := range report.Rounds {
+		if !r.Passed {
+			fmt.Printf("    ❌ Round #%d (%s): %s\n", r.RoundNumber, r.RoundID, r.Problem)
+		}
+	}
+	fmt.Printf("    Total pot:       %.3f TON\n", report.TotalPot)
+	fmt.Printf("    Total paid:      %.3f TON\n", report.TotalPaid)
+	fmt.Printf("    Total house cut: %.3f TON (expected %.3f)\n", report.TotalHouseCut, report.ExpectedHouseCut)
+	if report.Passed {
+		fmt.Printf("    ✅ All %d rounds paid the pot minus the house edge\n", len(report.Rounds))
+	} else if !report.balanced() && report.Discrepancy > 0 {
+		fmt.Printf("    ❌ %.3f TON of the pots was neither paid out nor kept as the house edge\n", report.Discrepancy)
+	} else if !report.balanced() {
+		fmt.Printf("    ❌ %.3f TON more was paid out than the pots minus the house edge\n", -report.Discrepancy)
+	}
+}
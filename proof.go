@@ -0,0 +1,148 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/lazyton/jackpot-verification/verify"
+)
+
+// Proof is the compact, shareable claim "I verified this round". Its URL
+// points at the round's public verify endpoint, so opening it shows the
+// data, and feeding it back with --proof re-checks the claim.
+type Proof struct {
+	RoundID  string `json:"round_id"`
+	Result   string `json:"result"`
+	Winner   string `json:"winner"`
+	Verified bool   `json:"verified"`
+	// Digest is the SHA-256 of the round's verification inputs, pinning
+	// the exact data that was verified
+	Digest string `json:"digest"`
+}
+
+// newProof summarizes a verified round
+func newProof(data verify.RoundVerificationData, result *verify.Report) Proof {
+	return Proof{
+		RoundID:  data.RoundID,
+		Result:   fmt.Sprintf("%.3f", data.Result),
+		Winner:   data.WinnerAddress,
+		Verified: result.Passed,
+		Digest:   proofDigest(data),
+	}
+}
+
+// proofDigest hashes the whole decoded round, every field verification may
+// read, as re-encoded by encoding/json: fields in declaration order and
+// numbers in their shortest form, so formatting of the JSON the round was
+// read from does not affect it. The pagination fields are cleared first, so
+// a round fetched page by page hashes like the same round read from a file.
+// Rounds that cannot be encoded (a NaN amount) have no digest.
+func proofDigest(data verify.RoundVerificationData) string {
+	data.NextCursor, data.TotalBets = "", 0
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// URL encodes the proof as a link to the round on the given API
+func (p Proof) URL(apiURL string) string {
+	q := url.Values{}
+	q.Set("round_id", p.RoundID)
+	q.Set("result", p.Result)
+	q.Set("winner", p.Winner)
+	q.Set("verified", strconv.FormatBool(p.Verified))
+	q.Set("proof", p.Digest)
+	return strings.TrimRight(apiURL, "/") + verifyEndpoint + "?" + q.Encode()
+}
+
+// parseProof decodes a proof URL, returning the proof and the API base URL
+// the round can be fetched from
+func parseProof(raw string) (Proof, string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return Proof{}, "", err
+	}
+	q := u.Query()
+	p := Proof{
+		RoundID: q.Get("round_id"),
+		Result:  q.Get("result"),
+		Winner:  q.Get("winner"),
+		Digest:  q.Get("proof"),
+	}
+	if p.RoundID == "" || p.Digest == "" {
+		return Proof{}, "", fmt.Errorf("not a proof link: round_id and proof are required")
+	}
+	if p.Verified, err = strconv.ParseBool(q.Get("verified")); err != nil {
+		return Proof{}, "", fmt.Errorf("not a proof link: invalid verified value %q", q.Get("verified"))
+	}
+
+	base := strings.TrimSuffix(u.Path, verifyEndpoint)
+	return p, u.Scheme + "://" + u.Host + base, nil
+}
+
+// proofAPIURL picks the API a proof's round is fetched from: the link's own
+// API, unless --api-url was given explicitly, in which case the two must
+// name the same API rather than one silently overriding the other
+func proofAPIURL(linkBase, apiURL string, explicit bool) (string, error) {
+	if explicit && strings.TrimRight(apiURL, "/") != strings.TrimRight(linkBase, "/") {
+		return "", fmt.Errorf("--api-url %s does not match the proof link's API %s", apiURL, linkBase)
+	}
+	return linkBase, nil
+}
+
+// ProofCheck compares one field of a shared proof with the recomputed value
+type ProofCheck struct {
+	Field    string `json:"field"`
+	Claimed  string `json:"claimed"`
+	Computed string `json:"computed"`
+	Passed   bool   `json:"passed"`
+}
+
+// checkProof recomputes the proof from the round data and compares it with
+// the shared one field by field
+func checkProof(shared Proof, data verify.RoundVerificationData, result *verify.Report) []ProofCheck {
+	computed := newProof(data, result)
+	fields := []struct{ name, claimed, computed string }{
+		{"round_id", shared.RoundID, computed.RoundID},
+		{"proof", shared.Digest, computed.Digest},
+		{"result", shared.Result, computed.Result},
+		{"winner", shared.Winner, computed.Winner},
+		{"verified", strconv.FormatBool(shared.Verified), strconv.FormatBool(computed.Verified)},
+	}
+
+	checks := make([]ProofCheck, len(fields))
+	for i, f := range fields {
+		checks[i] = ProofCheck{Field: f.name, Claimed: f.claimed, Computed: f.computed, Passed: f.claimed == f.computed}
+	}
+	return checks
+}
+
+func printProofChecks(roundID string, checks []ProofCheck) {
+	fmt.Printf("🔗 Checking shared proof for round %s\n", roundID)
+	fmt.Println(strings.Repeat("=", 60))
+	passed := true
+	for _, check := range checks {
+		if check.Passed {
+			fmt.Printf("    ✅ %s: %s\n", check.Field, check.Computed)
+			continue
+		}
+		passed = false
+		fmt.Printf("    ❌ %s mismatch!\n", check.Field)
+		fmt.Printf("       Shared:     %s\n", check.Claimed)
+		fmt.Printf("       Recomputed: %s\n", check.Computed)
+	}
+	fmt.Println(strings.Repeat("=", 60))
+	if passed {
+		fmt.Println("🎉 PROOF CONFIRMED! The shared claim matches the round data.")
+	} else {
+		fmt.Println("💀 PROOF REJECTED! The shared claim does not match the round data.")
+	}
+}
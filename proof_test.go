@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/lazyton/jackpot-verification/verify"
+)
+
+func TestProofDigestCoversRound(t *testing.T) {
+	var base verify.RoundVerificationData
+	if err := json.Unmarshal([]byte(prettyRound), &base); err != nil {
+		t.Fatal(err)
+	}
+	digest := proofDigest(base)
+
+	changes := []struct {
+		name   string
+		change func(*verify.RoundVerificationData)
+	}{
+		{name: "total_pot", change: func(d *verify.RoundVerificationData) { d.TotalPot = 1 }},
+		{name: "range_denominator", change: func(d *verify.RoundVerificationData) { d.RangeDenominator = 100 }},
+		{name: "seed_root", change: func(d *verify.RoundVerificationData) { d.SeedRoot = "ab" }},
+		{name: "shuffle", change: func(d *verify.RoundVerificationData) { d.ShuffleOutput = []string{"a"} }},
+		{name: "crash", change: func(d *verify.RoundVerificationData) { d.CrashMultiplier = 2 }},
+		{name: "shards", change: func(d *verify.RoundVerificationData) { d.ShardRoots = []string{"ab"} }},
+		{name: "bet order", change: func(d *verify.RoundVerificationData) { d.Bets[0], d.Bets[1] = d.Bets[1], d.Bets[0] }},
+		{name: "winner", change: func(d *verify.RoundVerificationData) { d.WinnerAddress = "EQC3zzzzzzzz6D4E" }},
+	}
+	for _, tt := range changes {
+		t.Run(tt.name, func(t *testing.T) {
+			data := base
+			data.Bets = append([]verify.VerificationBet(nil), base.Bets...)
+			tt.change(&data)
+			if proofDigest(data) == digest {
+				t.Errorf("changing %s leaves the proof digest unchanged", tt.name)
+			}
+		})
+	}
+
+	t.Run("verify options", func(t *testing.T) {
+		game := verify.GameRegistry[verify.DefaultGameName]
+		game.MinWinningBet = 14
+		opts := verify.Options{Game: game, AmountsAreShares: true, FallbackRule: verify.FallbackLast}
+		if proofDigest(opts.Apply(base)) != digest {
+			t.Error("applying the verify options changes the proof digest")
+		}
+	})
+
+	t.Run("formatting and pagination", func(t *testing.T) {
+		var compact verify.RoundVerificationData
+		if err := json.Unmarshal([]byte(strings.Join(strings.Fields(prettyRound), "")), &compact); err != nil {
+			t.Fatal(err)
+		}
+		compact.NextCursor, compact.TotalBets = "", len(compact.Bets)
+		if proofDigest(compact) != digest {
+			t.Error("reformatted round with a bet count has a different proof digest")
+		}
+	})
+}
+
+func TestProofURLRoundTrip(t *testing.T) {
+	want := Proof{RoundID: "round-1", Result: "54.898", Winner: "EQB2bbbbbbbb5C3D", Verified: true, Digest: "abc123"}
+	got, base, err := parseProof(want.URL("https://api.example.com/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want || base != "https://api.example.com" {
+		t.Errorf("parseProof(URL) = %+v, %q; want %+v, https://api.example.com", got, base, want)
+	}
+}
+
+func TestProofAPIURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		apiURL   string
+		explicit bool
+		want     string
+		wantErr  bool
+	}{
+		{name: "default is replaced by the link", apiURL: defaultAPIURL, want: "https://api.example.com"},
+		{name: "explicit and matching", apiURL: "https://api.example.com/", explicit: true, want: "https://api.example.com"},
+		{name: "explicit and different", apiURL: "https://other.example.com", explicit: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := proofAPIURL("https://api.example.com", tt.apiURL, tt.explicit)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("proofAPIURL() = %q, %v; want %q (error %v)", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/lazyton/jackpot-verification/verify"
+)
+
+// rangeLabel names a range's owner, marking the house's own bets
+func rangeLabel(house []string, address string) string {
+	if verify.IsHouse(house, address) {
+		return "🏠 House " + shortAddress(address)
+	}
+	return shortAddress(address)
+}
+
+// showRoundRanges prints the round's ranges, including the share held by
+// undisclosed bets when the round declares a range denominator. Under the
+// nearest winner rule each range's midpoint is shown and the trophy marks
+// the nearest one rather than the one containing the result. Bets by house
+// addresses are labeled as the house.
+func showRoundRanges(w io.Writer, data verify.RoundVerificationData, result float64, rule string, house []string) {
+	if len(data.Bets) == 0 {
+		fmt.Fprintln(w, "    No bets to show")
+		return
+	}
+	bets := data.WinningBets()
+	if excluded := len(data.Bets) - len(bets); excluded > 0 {
+		fmt.Fprintf(w, "    🪙 %d bet(s) below the %.3f TON minimum winning bet count toward the pot but cannot win\n",
+			excluded, data.MinWinningBet)
+	}
+	if len(bets) == 0 {
+		fmt.Fprintln(w, "    No bet can win")
+		return
+	}
+
+	if len(bets) == 1 && !data.HasHiddenShare() && !data.AmountsAreShares {
+		fmt.Fprintf(w, "    🏆 %s: 0.000 - 100.000 (100.0%% chance, %.2f TON)\n",
+			rangeLabel(house, bets[0].PlayerAddress), bets[0].Amount)
+		fmt.Fprintln(w, "    👤 Single participant — guaranteed winner for any result")
+		return
+	}
+
+	ranges, err := data.WinnerRanges()
+	if err != nil {
+		fmt.Fprintf(w, "    ❌ Cannot compute ranges: %v\n", err)
+		return
+	}
+
+	nearest := rule == verify.WinnerRuleNearest
+	nearestIndex := verify.NearestRange(ranges, result, data.HasHiddenShare())
+	for i, r := range ranges {
+		winnerIcon := "  "
+		if (!nearest && verify.RangeHolds(ranges, i, result)) || (nearest && i == nearestIndex) {
+			winnerIcon = "🏆"
+		}
+		midpoint := ""
+		if nearest {
+			midpoint = fmt.Sprintf(" midpoint %.3f", r.Midpoint())
+		}
+
+		if data.AmountsAreShares {
+			fmt.Fprintf(w, "    %s %s: %.3f - %.3f%s (%.1f%% chance)\n",
+				winnerIcon, rangeLabel(house, r.Player), r.Start, r.End, midpoint, r.Percent)
+			continue
+		}
+		fmt.Fprintf(w, "    %s %s: %.3f - %.3f%s (%.1f%% chance, %.2f TON)\n",
+			winnerIcon, rangeLabel(house, r.Player), r.Start, r.End, midpoint, r.Percent, r.Amount)
+	}
+
+	if data.HasHiddenShare() {
+		last := ranges[len(ranges)-1].End
+		hiddenIcon := "  "
+		if (!nearest && result >= last) || (nearest && nearestIndex < 0) {
+			hiddenIcon = "🏆"
+		}
+		fmt.Fprintf(w, "    %s Undisclosed bets: %.3f - 100.000 (%.1f%% chance, %.2f of %.2f TON declared)\n",
+			hiddenIcon, last, 100.0-last, data.RangeDenominator*(100.0-last)/100.0, data.RangeDenominator)
+	}
+
+	coverage := verify.ComputeRangeCoverage(ranges, data.HasHiddenShare())
+	if coverage.Complete {
+		fmt.Fprintf(w, "    📏 Ranges cover %.3f%% of the domain with no gaps or overlaps\n", coverage.Total)
+	} else if !coverage.Contiguous {
+		fmt.Fprintf(w, "    ⚠️  Ranges have gaps or overlaps; they reach %.3f%% of the domain\n", coverage.Total)
+	} else {
+		fmt.Fprintf(w, "    ⚠️  Ranges cover %.3f%% of the domain, not 100%%\n", coverage.Total)
+	}
+
+	if nearest {
+		fmt.Fprintf(w, "    🎯 Result %.3f is nearest the winner's range midpoint\n", result)
+		return
+	}
+	fmt.Fprintf(w, "    🎯 Result %.3f falls in winner's range\n", result)
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/lazyton/jackpot-verification/verify"
+)
+
+// BetReceipt is the signed acknowledgement a player receives when betting.
+// The operator signs, with ed25519, the round id, a "|", and the bet
+// serialized exactly as for the client seed by AppendBet (address, amount
+// with three decimals and gift id, no separators).
+type BetReceipt struct {
+	RoundID       string  `json:"round_id"`
+	PlayerAddress string  `json:"player_address"`
+	Amount        float64 `json:"amount"`
+	GiftID        string  `json:"gift_id"`
+	Signature     string  `json:"signature"`
+}
+
+// Names of the receipt checks
+const (
+	CheckReceiptSignature = "receipt_signature"
+	CheckReceiptRound     = "receipt_round"
+	CheckReceiptIncluded  = "bet_included"
+)
+
+// bet returns the bet the receipt acknowledges
+func (r BetReceipt) bet() verify.VerificationBet {
+	return verify.VerificationBet{PlayerAddress: r.PlayerAddress, Amount: r.Amount, GiftID: r.GiftID}
+}
+
+// message returns the bytes the operator signed
+func (r BetReceipt) message() string {
+	return r.RoundID + "|" + string(verify.AppendBet(nil, r.bet()))
+}
+
+// loadBetReceipt reads a receipt from a file or inline JSON
+func loadBetReceipt(input string) (BetReceipt, error) {
+	var receipt BetReceipt
+	raw, err := readInputFile(input)
+	if err != nil {
+		raw = []byte(input)
+	}
+	if err := json.Unmarshal(raw, &receipt); err != nil {
+		return receipt, fmt.Errorf("Failed to parse bet receipt: %v", err)
+	}
+	return receipt, nil
+}
+
+// verifyReceipt proves a bet was included in a round: the receipt is signed
+// by the operator key, is for this round, names a bet that appears exactly
+// in the round's bet list, and that bet list is the one the client seed
+// commits to.
+func verifyReceipt(receipt BetReceipt, keyHex string, data verify.RoundVerificationData, opts verify.Options) []verify.Check {
+	signature := verify.Check{Name: CheckReceiptSignature, Expected: "valid signature", Actual: "invalid signature"}
+	if err := verifyEd25519(keyHex, receipt.Signature, receipt.message(), "the receipt"); err != nil {
+		signature.Error = err.Error()
+	} else {
+		signature.Passed = true
+		signature.Actual = "valid signature"
+	}
+
+	round := verify.Check{
+		Name:     CheckReceiptRound,
+		Passed:   receipt.RoundID == data.RoundID,
+		Expected: data.RoundID,
+		Actual:   receipt.RoundID,
+	}
+
+	want := string(verify.AppendBet(nil, receipt.bet()))
+	included := verify.Check{Name: CheckReceiptIncluded, Expected: want, Actual: "not in the bet list"}
+	for i, bet := range data.Bets {
+		if string(verify.AppendBet(nil, bet)) == want {
+			included.Passed = true
+			included.Actual = fmt.Sprintf("bet %d of %d", i+1, len(data.Bets))
+			break
+		}
+	}
+
+	result := verify.VerifyRoundWithOptions(data, opts)
+	clientSeed := verify.Check{Name: verify.CheckClientSeed, Expected: "bet list hashes to the client seed"}
+	if check := result.Check(verify.CheckClientSeed); check != nil {
+		clientSeed.Passed = check.Passed && !check.Skipped
+		clientSeed.Actual = check.Expected
+		clientSeed.Error = check.Error
+	}
+
+	return []verify.Check{signature, round, included, clientSeed}
+}
+
+// printReceiptChecks prints the outcome of verifying a bet receipt
+func printReceiptChecks(receipt BetReceipt, checks []verify.Check) {
+	fmt.Printf("🧾 Verifying Bet Receipt: %s bet %.3f TON in round %s\n", receipt.PlayerAddress, receipt.Amount, receipt.RoundID)
+	fmt.Println(strings.Repeat("=", 60))
+	passed := true
+	for _, check := range checks {
+		passed = passed && check.Passed
+		switch check.Name {
+		case CheckReceiptSignature:
+			if check.Passed {
+				fmt.Println("    ✅ Receipt is signed by the operator key")
+			} else {
+				fmt.Printf("    ❌ Invalid receipt signature: %s\n", check.Error)
+			}
+		case CheckReceiptRound:
+			if check.Passed {
+				fmt.Printf("    ✅ Receipt is for this round: %s\n", check.Actual)
+			} else {
+				fmt.Printf("    ❌ Receipt is for round %s, not %s\n", check.Actual, check.Expected)
+			}
+		case CheckReceiptIncluded:
+			if check.Passed {
+				fmt.Printf("    ✅ Bet appears in the round's bet list (%s)\n", check.Actual)
+			} else {
+				fmt.Println("    ❌ Bet is missing from the round's bet list (address, amount and gift id must all match)")
+			}
+		case verify.CheckClientSeed:
+			if check.Passed {
+				fmt.Println("    ✅ The bet list is the one the client seed commits to")
+			} else {
+				fmt.Println("    ❌ The bet list does not hash to the round's client seed")
+			}
+		}
+	}
+	fmt.Println(strings.Repeat("=", 60))
+	if passed {
+		fmt.Println("🎉 BET INCLUDED! The receipted bet was counted in the draw.")
+	} else {
+		fmt.Println("💀 INCLUSION NOT PROVEN! The receipted bet may have been dropped or altered.")
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lazyton/jackpot-verification/verify"
+)
+
+// Redactor replaces player addresses with stable pseudonyms for display.
+// Pseudonyms follow the sorted address order and are zero-padded so they
+// sort the same way, which keeps ranges recomputed from redacted bets
+// identical to the real ones.
+type Redactor struct {
+	names    map[string]string
+	order    []string
+	unlisted int
+}
+
+func newRedactor(bets []verify.VerificationBet) *Redactor {
+	r := &Redactor{names: make(map[string]string)}
+	for _, bet := range bets {
+		if _, ok := r.names[bet.PlayerAddress]; !ok {
+			r.names[bet.PlayerAddress] = ""
+			r.order = append(r.order, bet.PlayerAddress)
+		}
+	}
+	sort.Strings(r.order)
+
+	width := len(fmt.Sprint(len(r.order)))
+	for i, address := range r.order {
+		r.names[address] = fmt.Sprintf("Player-%0*d", width, i+1)
+	}
+	return r
+}
+
+// Name returns the pseudonym for an address. Addresses that placed no bet
+// (e.g. a bogus claimed winner) get their own "Unlisted-N" pseudonym.
+func (r *Redactor) Name(address string) string {
+	if address == "" {
+		return ""
+	}
+	if name, ok := r.names[address]; ok {
+		return name
+	}
+	r.unlisted++
+	name := fmt.Sprintf("Unlisted-%d", r.unlisted)
+	r.names[address] = name
+	r.order = append(r.order, address)
+	return name
+}
+
+// Text replaces every known address that appears in s as a whole token.
+// Longer addresses are tried first and a match must not run into further
+// address characters, so an address that is a prefix or substring of
+// another is never rewritten inside it.
+func (r *Redactor) Text(s string) string {
+	addresses := make([]string, 0, len(r.order))
+	for _, address := range r.order {
+		if address != "" {
+			addresses = append(addresses, address)
+		}
+	}
+	sort.SliceStable(addresses, func(i, j int) bool {
+		return len(addresses[i]) > len(addresses[j])
+	})
+
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		if i == 0 || !isAddressByte(s[i-1]) {
+			if address := matchAddress(s[i:], addresses); address != "" {
+				b.WriteString(r.names[address])
+				i += len(address)
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+		i++
+	}
+	return b.String()
+}
+
+// matchAddress returns the first of addresses that s starts with as a whole
+// token, or "" when none does
+func matchAddress(s string, addresses []string) string {
+	for _, address := range addresses {
+		if strings.HasPrefix(s, address) && (len(s) == len(address) || !isAddressByte(s[len(address)])) {
+			return address
+		}
+	}
+	return ""
+}
+
+// isAddressByte reports whether b can continue a TON address: letters,
+// digits and the symbols of the standard and URL-safe base64 forms
+func isAddressByte(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9' || strings.IndexByte("+/-_", b) >= 0
+}
+
+// Round returns a copy of the round with every address replaced
+func (r *Redactor) Round(data verify.RoundVerificationData) verify.RoundVerificationData {
+	redacted := data
+	redacted.Bets = make([]verify.VerificationBet, len(data.Bets))
+	for i, bet := range data.Bets {
+		bet.PlayerAddress = r.Name(bet.PlayerAddress)
+		redacted.Bets[i] = bet
+	}
+	redacted.WinnerAddress = r.Name(data.WinnerAddress)
+	return redacted
+}
+
+// Result returns a copy of the result with every address replaced
+func (r *Redactor) Result(result *verify.Report) *verify.Report {
+	redacted := *result
+	redacted.ClaimedWinner = r.Name(result.ClaimedWinner)
+	redacted.ComputedWinner = r.Name(result.ComputedWinner)
+	if result.HouseAddresses != nil {
+		redacted.HouseAddresses = make([]string, len(result.HouseAddresses))
+		for i, address := range result.HouseAddresses {
+			redacted.HouseAddresses[i] = r.Name(address)
+		}
+	}
+
+	if result.WinnerRanges != nil {
+		redacted.WinnerRanges = make([]verify.WinnerRange, len(result.WinnerRanges))
+		for i, wr := range result.WinnerRanges {
+			wr.Player = r.Name(wr.Player)
+			redacted.WinnerRanges[i] = wr
+		}
+	}
+
+	if result.ClaimedRange != nil {
+		claimed := *result.ClaimedRange
+		claimed.Player = r.Name(claimed.Player)
+		redacted.ClaimedRange = &claimed
+	}
+
+	// Claimed addresses are named first so that any unlisted ones are
+	// known to Text when they turn up in messages
+	redacted.Checks = make([]verify.Check, len(result.Checks))
+	for i, check := range result.Checks {
+		if check.Name == verify.CheckWinner || check.Name == verify.CheckExpectedWinner {
+			check.Expected = r.Name(check.Expected)
+			check.Actual = r.Name(check.Actual)
+		}
+		redacted.Checks[i] = check
+	}
+	for i, check := range redacted.Checks {
+		check.Expected = r.Text(check.Expected)
+		check.Actual = r.Text(check.Actual)
+		check.Error = r.Text(check.Error)
+		redacted.Checks[i] = check
+	}
+	if result.Alerts != nil {
+		redacted.Alerts = make([]string, len(result.Alerts))
+		for i, alert := range result.Alerts {
+			redacted.Alerts[i] = r.Text(alert)
+		}
+	}
+
+	if result.TieBreak != nil {
+		tie := *result.TieBreak
+		tie.Lower = r.Name(tie.Lower)
+		tie.Upper = r.Name(tie.Upper)
+		tie.Winner = r.Name(tie.Winner)
+		redacted.TieBreak = &tie
+	}
+
+	if result.Trace != nil {
+		trace := &verify.Trace{Steps: make([]verify.TraceStep, len(result.Trace.Steps))}
+		for i, step := range result.Trace.Steps {
+			if strings.HasSuffix(step.Label, ".player_address") {
+				step.Value = "redacted"
+			} else {
+				step.Value = r.Text(step.Value)
+			}
+			trace.Steps[i] = step
+		}
+		redacted.Trace = trace
+	}
+	return &redacted
+}
+
+// isPseudonym reports whether a display name was produced by a Redactor, so
+// it can be shown in full instead of abbreviated like an address
+func isPseudonym(name string) bool {
+	return strings.HasPrefix(name, "Player-") || strings.HasPrefix(name, "Unlisted-")
+}
+
+// printRedactionMap shows which pseudonym stands for which address
+func printRedactionMap(r *Redactor) {
+	fmt.Println("🕶️  Redaction Map:")
+	for _, address := range r.order {
+		fmt.Printf("    %s = %s\n", r.names[address], address)
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/lazyton/jackpot-verification/verify"
+)
+
+func TestRedactorText(t *testing.T) {
+	r := newRedactor([]verify.VerificationBet{
+		{PlayerAddress: "EQab"},
+		{PlayerAddress: "EQabcd"},
+		{PlayerAddress: "EQxEQab"},
+	})
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "prefix of another address", text: "gift g1 bet by EQab and EQabcd", want: "gift g1 bet by Player-1 and Player-2"},
+		{name: "longer address first", text: "EQabcd, EQab", want: "Player-2, Player-1"},
+		{name: "suffix of another address", text: "bet by EQxEQab", want: "bet by Player-3"},
+		{name: "address inside a longer token", text: "EQabc is unknown", want: "EQabc is unknown"},
+		{name: "punctuation ends an address", text: "(EQab). EQabcd:", want: "(Player-1). Player-2:"},
+		{name: "no addresses", text: "bets total 0", want: "bets total 0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.Text(tt.text); got != tt.want {
+				t.Errorf("Text(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactorResultHidesAddresses(t *testing.T) {
+	const (
+		alice = "EQAliceAliceAliceAliceAliceAliceAliceAliceAlic"
+		bob   = "EQBobBobBobBobBobBobBobBobBobBobBobBobBobBobBo"
+		eve   = "EQEveEveEveEveEveEveEveEveEveEveEveEveEveEveEv"
+	)
+	tieGame := verify.GameRegistry[verify.DefaultGameName]
+	tieGame.TieBreak = true
+
+	tests := []struct {
+		name string
+		data verify.RoundVerificationData
+		game verify.GameConfig
+	}{
+		{name: "claimed range and unlisted winner", data: verify.RoundVerificationData{
+			Success: true, Result: 25, WinnerAddress: eve,
+			Bets: []verify.VerificationBet{{PlayerAddress: alice, Amount: 1}, {PlayerAddress: bob, Amount: 1}},
+		}},
+		{name: "wrong listed winner", data: verify.RoundVerificationData{
+			Success: true, Result: 75, WinnerAddress: alice,
+			Bets: []verify.VerificationBet{{PlayerAddress: alice, Amount: 1}, {PlayerAddress: bob, Amount: 1}},
+		}},
+		{name: "tie-break", game: tieGame, data: verify.RoundVerificationData{
+			Success: true, Result: 50, WinnerAddress: alice,
+			Bets: []verify.VerificationBet{{PlayerAddress: alice, Amount: 1}, {PlayerAddress: bob, Amount: 1}},
+		}},
+		{name: "range math error", data: verify.RoundVerificationData{
+			Success: true, Result: 50, WinnerAddress: alice,
+			Bets: []verify.VerificationBet{{PlayerAddress: alice, Amount: math.MaxFloat64}, {PlayerAddress: bob, Amount: math.MaxFloat64}},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game := tt.game
+			if game.Name == "" {
+				game = verify.GameRegistry[verify.DefaultGameName]
+			}
+			result := verify.VerifyRoundWithOptions(tt.data, verify.Options{Game: game, Trace: true})
+			redacted := newRedactor(tt.data.Bets).Result(result)
+
+			out, err := json.Marshal(redacted)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, address := range []string{alice, bob, eve} {
+				if strings.Contains(string(out), address) {
+					t.Errorf("redacted result contains %s:\n%s", address, out)
+				}
+			}
+		})
+	}
+}
+
+func TestRedactorIneligibleWinner(t *testing.T) {
+	const (
+		alice = "EQAliceAliceAliceAliceAliceAliceAliceAliceAlic"
+		bob   = "EQBobBobBobBobBobBobBobBobBobBobBobBobBobBobBo"
+	)
+	game := verify.GameRegistry[verify.DefaultGameName]
+	game.MinWinningBet = 5
+	data := verify.RoundVerificationData{
+		Success: true, Result: 50, WinnerAddress: alice,
+		Bets: []verify.VerificationBet{{PlayerAddress: alice, Amount: 1}, {PlayerAddress: bob, Amount: 10}},
+	}
+	result := verify.VerifyRoundWithOptions(data, verify.Options{Game: game})
+	check := newRedactor(data.Bets).Result(result).Check(verify.CheckWinner)
+	want := "Ineligible winner: Player-1 placed no bet of at least the minimum winning bet 5.000 TON; the calculated winner is Player-2"
+	if check == nil || check.Error != want {
+		t.Fatalf("redacted winner check = %+v, want error %q", check, want)
+	}
+}
+
+func TestStreamRoundsRedacts(t *testing.T) {
+	var data verify.RoundVerificationData
+	if err := json.Unmarshal([]byte(prettyRound), &data); err != nil {
+		t.Fatal(err)
+	}
+	result := verify.VerifyRoundWithOptions(data, verify.Options{Game: verify.GameRegistry[verify.DefaultGameName]})
+	round := BatchRound{Source: "round.json", Status: StatusPassed, Result: result, Data: &data}
+
+	for _, redact := range []bool{false, true} {
+		var out strings.Builder
+		streamRounds(newNDJSONWriter(&out), redact)(round)
+		for _, bet := range data.Bets {
+			if got := strings.Contains(out.String(), bet.PlayerAddress); got == redact {
+				t.Errorf("redact=%v: line contains %s = %v:\n%s", redact, bet.PlayerAddress, got, out.String())
+			}
+		}
+	}
+	if round.Result != result {
+		t.Error("streaming replaced the caller's result")
+	}
+}
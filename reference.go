@@ -0,0 +1,221 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lazyton/jackpot-verification/verify"
+)
+
+// referenceTemplates are self-contained implementations of the commitment,
+// client seed and result steps, using only each language's standard library.
+// The $NAME placeholders are filled from the game profile by emitReference,
+// so the constants always match what the verifier itself uses.
+var referenceTemplates = map[string]string{
+	"python": `#!/usr/bin/env python3
+# Reference implementation of the LazyBox provably fair algorithm for game $GAME,
+# generated by jackpot-verification $VERSION. Standard library only.
+#
+# Usage: python3 reference.py round.json
+import hashlib
+import hmac
+import json
+import math
+import sys
+
+HASH = hashlib.$HASH
+MODULUS = $MODULUS
+DIVISOR = $DIVISOR
+MESSAGE_FORMAT = $MESSAGE_FORMAT
+CLIENT_SEED_MODE = $CLIENT_SEED_MODE
+CLIENT_SEED_KEY = $CLIENT_SEED_KEY
+BET_ORDER = $BET_ORDER
+ROUNDING = $ROUNDING
+
+
+def server_hash(server_seed):
+    return HASH(server_seed.encode()).hexdigest()
+
+
+def serialize_bet(bet):
+    # Go's %.3f rounds the exact binary value, as Python's format does
+    return (bet["player_address"] + format(float(bet["amount"]), ".3f") + bet.get("gift_id", "")).encode()
+
+
+def client_seed(bets):
+    if BET_ORDER == "sorted":
+        # Stable sort by address in byte order
+        bets = sorted(bets, key=lambda bet: bet["player_address"].encode())
+    payload = b"".join(serialize_bet(bet) for bet in bets)
+    if CLIENT_SEED_MODE == "hmac":
+        return hmac.new(CLIENT_SEED_KEY.encode(), payload, hashlib.sha256).hexdigest()
+    return hashlib.sha256(payload).hexdigest()
+
+
+def message(server_seed, client_seed, round_number, previous_hash):
+    return (MESSAGE_FORMAT
+            .replace("{server_seed}", server_seed)
+            .replace("{client_seed}", client_seed)
+            .replace("{round_number}", str(round_number))
+            .replace("{previous_hash}", previous_hash))
+
+
+def result(server_seed, client_seed, round_number, previous_hash=""):
+    digest = hmac.new(server_seed.encode(), message(server_seed, client_seed, round_number, previous_hash).encode(), HASH).digest()
+    return (int.from_bytes(digest, "big") % MODULUS) / DIVISOR
+
+
+def format_result(value):
+    if ROUNDING == "truncate":
+        value = math.floor(value * 1000 + 1e-9) / 1000
+    elif ROUNDING == "ceil":
+        value = math.ceil(value * 1000 - 1e-9) / 1000
+    return format(value, ".3f")
+
+
+if __name__ == "__main__":
+    with open(sys.argv[1]) as f:
+        data = json.load(f)
+    seed = client_seed(data["bets"])
+    value = result(data["server_seed"], data["client_seed"], data["round_number"], data.get("previous_hash", ""))
+    checks = [
+        ("server_hash", server_hash(data["server_seed"]), data["server_hash"]),
+        ("client_seed", seed, data["client_seed"]),
+        ("result", format_result(value), format_result(data["result"])),
+    ]
+    for name, computed, claimed in checks:
+        print("%-12s %s computed=%s claimed=%s" % (name, "ok  " if computed == claimed else "FAIL", computed, claimed))
+    sys.exit(0 if all(computed == claimed for _, computed, claimed in checks) else 1)
+`,
+
+	"javascript": `#!/usr/bin/env node
+// Reference implementation of the LazyBox provably fair algorithm for game $GAME,
+// generated by jackpot-verification $VERSION. Node.js standard library only.
+//
+// Usage: node reference.js round.json
+"use strict";
+const crypto = require("crypto");
+const fs = require("fs");
+
+const HASH = $HASH;
+const MODULUS = $MODULUSn;
+const DIVISOR = $DIVISOR;
+const MESSAGE_FORMAT = $MESSAGE_FORMAT;
+const CLIENT_SEED_MODE = $CLIENT_SEED_MODE;
+const CLIENT_SEED_KEY = $CLIENT_SEED_KEY;
+const BET_ORDER = $BET_ORDER;
+const ROUNDING = $ROUNDING;
+
+function serverHash(serverSeed) {
+  return crypto.createHash(HASH).update(serverSeed, "utf8").digest("hex");
+}
+
+function serializeBet(bet) {
+  // toFixed rounds the exact binary value like Go's %.3f, except that an
+  // exactly representable tie rounds up here and to even in Go
+  return bet.player_address + Number(bet.amount).toFixed(3) + (bet.gift_id || "");
+}
+
+function clientSeed(bets) {
+  if (BET_ORDER === "sorted") {
+    // Array.prototype.sort is stable; compare addresses in byte order
+    bets = [...bets].sort((a, b) => Buffer.compare(Buffer.from(a.player_address), Buffer.from(b.player_address)));
+  }
+  const payload = Buffer.from(bets.map(serializeBet).join(""), "utf8");
+  const h = CLIENT_SEED_MODE === "hmac" ? crypto.createHmac("sha256", CLIENT_SEED_KEY) : crypto.createHash("sha256");
+  return h.update(payload).digest("hex");
+}
+
+function message(serverSeed, clientSeed, roundNumber, previousHash) {
+  return MESSAGE_FORMAT
+    .split("{server_seed}").join(serverSeed)
+    .split("{client_seed}").join(clientSeed)
+    .split("{round_number}").join(String(roundNumber))
+    .split("{previous_hash}").join(previousHash);
+}
+
+function result(serverSeed, clientSeed, roundNumber, previousHash = "") {
+  const digest = crypto.createHmac(HASH, serverSeed).update(message(serverSeed, clientSeed, roundNumber, previousHash), "utf8").digest("hex");
+  return Number(BigInt("0x" + digest) % MODULUS) / DIVISOR;
+}
+
+function formatResult(value) {
+  if (ROUNDING === "truncate") {
+    value = Math.floor(value * 1000 + 1e-9) / 1000;
+  } else if (ROUNDING === "ceil") {
+    value = Math.ceil(value * 1000 - 1e-9) / 1000;
+  }
+  return value.toFixed(3);
+}
+
+if (require.main === module) {
+  const data = JSON.parse(fs.readFileSync(process.argv[2], "utf8"));
+  const value = result(data.server_seed, data.client_seed, data.round_number, data.previous_hash || "");
+  const checks = [
+    ["server_hash", serverHash(data.server_seed), data.server_hash],
+    ["client_seed", clientSeed(data.bets), data.client_seed],
+    ["result", formatResult(value), formatResult(data.result)],
+  ];
+  for (const [name, computed, claimed] of checks) {
+    console.log(name.padEnd(12) + " " + (computed === claimed ? "ok  " : "FAIL") + " computed=" + computed + " claimed=" + claimed);
+  }
+  process.exit(checks.every(([, computed, claimed]) => computed === claimed) ? 0 : 1);
+}
+
+module.exports = { serverHash, clientSeed, result, formatResult };
+`,
+}
+
+// referenceAliases maps accepted --emit-reference names to templates
+var referenceAliases = map[string]string{
+	"python":     "python",
+	"py":         "python",
+	"javascript": "javascript",
+	"js":         "javascript",
+	"node":       "javascript",
+}
+
+// emitReference prints the reference implementation in lang for game
+func emitReference(lang string, game verify.GameConfig) error {
+	template, ok := referenceTemplates[referenceAliases[strings.ToLower(lang)]]
+	if !ok {
+		names := make([]string, 0, len(referenceTemplates))
+		for name := range referenceTemplates {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("no reference implementation for %q (want %s)", lang, strings.Join(names, " or "))
+	}
+
+	mode, order, rounding := game.ClientSeedMode, game.BetOrder, game.ResultRounding
+	if mode == "" {
+		mode = verify.ClientSeedSHA256
+	}
+	if order == "" {
+		order = verify.BetOrderSorted
+	}
+	if rounding == "" {
+		rounding = verify.RoundingRound
+	}
+	// The hash name is an identifier in Python and a string in JavaScript
+	hashName := strconv.Quote(game.HashAlgorithm)
+	if referenceAliases[strings.ToLower(lang)] == "python" {
+		hashName = game.HashAlgorithm
+	}
+
+	fmt.Print(strings.NewReplacer(
+		"$GAME", game.Name,
+		"$VERSION", verify.VersionString(),
+		"$HASH", hashName,
+		"$MODULUS", strconv.FormatInt(game.Modulus, 10),
+		"$DIVISOR", strconv.FormatFloat(game.Divisor, 'f', -1, 64),
+		"$MESSAGE_FORMAT", strconv.Quote(game.MessageFormat),
+		"$CLIENT_SEED_MODE", strconv.Quote(mode),
+		"$CLIENT_SEED_KEY", strconv.Quote(game.ClientSeedKey),
+		"$BET_ORDER", strconv.Quote(order),
+		"$ROUNDING", strconv.Quote(rounding),
+	).Replace(template))
+	return nil
+}
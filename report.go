@@ -0,0 +1,311 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/lazyton/jackpot-verification/verify"
+)
+
+// printHeader prints the round summary shown before the checks
+func printHeader(w io.Writer, data verify.RoundVerificationData, game verify.GameConfig) {
+	fmt.Fprintf(w, "🎰 Verifying Jackpot Round #%d (%s)\n", data.RoundNumber, data.RoundID)
+	if game.Name != verify.DefaultGameName {
+		fmt.Fprintf(w, "🎲 Game: %s\n", game.Name)
+	}
+	fmt.Fprintf(w, "📊 Total Pot: %.2f TON\n", data.TotalPot)
+	fmt.Fprintf(w, "🎯 Claimed Result: %.3f\n", data.Result)
+	if data.Cancelled {
+		fmt.Fprintln(w, "🚫 Round cancelled: bets refunded, no winner")
+	}
+	if !data.Cancelled || data.WinnerAddress != "" {
+		fmt.Fprintf(w, "🏆 Claimed Winner: %s\n", data.WinnerAddress)
+	}
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+}
+
+// printReport renders a verification result in the numbered human format
+func printReport(w io.Writer, data verify.RoundVerificationData, result *verify.Report) {
+	printHeader(w, data, verify.GameRegistry[result.Game])
+
+	if check := result.Check(verify.CheckBetAmounts); check != nil && !check.Skipped {
+		fmt.Fprintln(w, "🧮 Checking Bet Amounts...")
+		if check.Passed {
+			fmt.Fprintf(w, "    ✅ %s\n", check.Actual)
+		} else {
+			for _, problem := range strings.Split(check.Error, "; ") {
+				fmt.Fprintf(w, "    ❌ %s\n", problem)
+			}
+		}
+	}
+
+	if check := result.Check(verify.CheckUniqueGifts); check != nil {
+		fmt.Fprintln(w, "🎁 Checking Gift Ids...")
+		if check.Skipped {
+			printSkippedCheck(w, check)
+		} else if check.Passed {
+			fmt.Fprintf(w, "    ✅ %s, none bet twice\n", check.Actual)
+		} else {
+			for _, problem := range strings.Split(check.Error, "; ") {
+				fmt.Fprintf(w, "    ❌ %s\n", problem)
+			}
+		}
+	}
+
+	if check := result.Check(verify.CheckServerHash); check != nil {
+		fmt.Fprintln(w, "1️⃣  Verifying Server Hash...")
+		if c := result.Commitment; c != nil {
+			fmt.Fprintf(w, "    📌 Using commitment published at %s: %s\n", c.Source, verify.ShortHash(c.Hash))
+			if c.URL != c.Source {
+				fmt.Fprintf(w, "       fetched via %s\n", c.URL)
+			}
+		}
+		if data.SeedRoot != "" {
+			fmt.Fprintf(w, "    🌳 Seed #%d of a committed batch, proven by %d hash(es) against Merkle root %s\n",
+				data.SeedIndex, len(data.SeedProof), verify.ShortHash(data.SeedRoot))
+		}
+		if check.Skipped {
+			printSkippedCheck(w, check)
+		} else if check.Passed {
+			fmt.Fprintf(w, "    ✅ Server hash matches: %s\n", verify.ShortHash(check.Actual))
+		} else {
+			fmt.Fprintf(w, "    ❌ Server hash mismatch!\n")
+			if check.Error != "" {
+				fmt.Fprintf(w, "    ⚠️  %s\n", check.Error)
+			}
+			printHashDiff(w, "Expected", check.Expected, "Got", check.Actual)
+		}
+	}
+
+	if check := result.Check(verify.CheckClientSeed); check != nil {
+		fmt.Fprintln(w, "2️⃣  Verifying Client Seed...")
+		if check.Skipped {
+			printSkippedCheck(w, check)
+		} else if check.Passed {
+			fmt.Fprintf(w, "    ✅ Client seed matches: %s\n", verify.ShortHash(check.Actual))
+		} else {
+			fmt.Fprintf(w, "    ❌ Client seed mismatch!\n")
+			if check.Error != "" {
+				fmt.Fprintf(w, "    ⚠️  %s\n", check.Error)
+			} else {
+				printHashDiff(w, "Calculated", check.Expected, "Claimed", check.Actual)
+			}
+		}
+	}
+
+	if check := result.Check(verify.CheckResult); check != nil {
+		fmt.Fprintln(w, "3️⃣  Verifying Result Calculation...")
+		if check.Skipped {
+			printSkippedCheck(w, check)
+		} else if check.Passed {
+			fmt.Fprintf(w, "    ✅ Result matches: %s\n", check.Actual)
+		} else {
+			fmt.Fprintf(w, "    ❌ Result mismatch!\n")
+			fmt.Fprintf(w, "       Calculated: %s\n", check.Expected)
+			fmt.Fprintf(w, "       Claimed:    %s\n", check.Actual)
+		}
+		if d := result.Derivation; d != nil {
+			printDerivation(w, d)
+		}
+	}
+
+	if check := result.Check(verify.CheckWinner); check != nil {
+		fmt.Fprintln(w, "4️⃣  Verifying Winner Selection...")
+		if tie := result.TieBreak; tie != nil {
+			fmt.Fprintf(w, "    🎲 Result lands on the %.3f boundary between %s and %s\n",
+				tie.Boundary, shortAddress(tie.Lower), shortAddress(tie.Upper))
+			fmt.Fprintf(w, "       Tie-break draw %s picks %s\n", verify.ShortHash(tie.Draw), shortAddress(tie.Winner))
+		}
+		if check.Skipped {
+			printSkippedCheck(w, check)
+		} else if result.Cancelled && check.Passed {
+			fmt.Fprintln(w, "    ✅ Round cancelled — no winner declared")
+		} else if result.Cancelled {
+			fmt.Fprintf(w, "    ❌ Round cancelled but a winner was declared: %s\n", check.Actual)
+		} else if check.Error != "" {
+			fmt.Fprintf(w, "    ❌ %s\n", check.Error)
+		} else if check.Passed {
+			fmt.Fprintf(w, "    ✅ Winner matches: %s\n", check.Actual)
+			if result.HouseWon {
+				fmt.Fprintln(w, "    🏠 The house bet won this round; the pot stays with the operator")
+			}
+		} else {
+			fmt.Fprintf(w, "    ❌ Winner mismatch!\n")
+			fmt.Fprintf(w, "       Calculated: %s\n", check.Expected)
+			fmt.Fprintf(w, "       Claimed:    %s\n", check.Actual)
+		}
+		if r := result.ClaimedRange; r != nil && !check.Skipped {
+			printRangeAssertion(w, r)
+		}
+	}
+
+	for _, alert := range result.Alerts {
+		fmt.Fprintf(w, "    🚨 %s\n", alert)
+	}
+
+	if check := result.Check(verify.CheckBeacon); check != nil {
+		fmt.Fprintf(w, "🛰️  Verifying Randomness Beacon (drand round %d)...\n", data.BeaconRound)
+		if check.Skipped {
+			printSkippedCheck(w, check)
+		} else if check.Passed {
+			fmt.Fprintf(w, "    ✅ Beacon value confirmed by the drand relays: %s\n", verify.ShortHash(check.Actual))
+			fmt.Fprintln(w, "       The client seed hashes it after the bets")
+		} else {
+			for _, problem := range strings.Split(check.Error, "; ") {
+				fmt.Fprintf(w, "    ❌ %s\n", problem)
+			}
+		}
+	}
+
+	if check := result.Check(verify.CheckCrashMultiplier); check != nil {
+		fmt.Fprintln(w, "💥 Verifying Crash Multiplier...")
+		if check.Skipped {
+			printSkippedCheck(w, check)
+		} else if check.Passed {
+			fmt.Fprintf(w, "    ✅ Crash multiplier matches: %sx\n", check.Actual)
+		} else {
+			fmt.Fprintf(w, "    ❌ Crash multiplier mismatch!\n")
+			fmt.Fprintf(w, "       Calculated: %sx (99 / (100 - %.3f), rounded down)\n", check.Expected, data.Result)
+			fmt.Fprintf(w, "       Claimed:    %sx\n", check.Actual)
+		}
+	}
+
+	if check := result.Check(verify.CheckShuffle); check != nil {
+		fmt.Fprintln(w, "🃏 Verifying Shuffle...")
+		if check.Skipped {
+			printSkippedCheck(w, check)
+		} else if check.Passed {
+			fmt.Fprintf(w, "    ✅ Shuffled order matches (%d items)\n", len(data.ShuffleOutput))
+		} else {
+			fmt.Fprintf(w, "    ❌ Shuffle mismatch: %s\n", check.Error)
+			fmt.Fprintf(w, "       Calculated: %s\n", truncate(check.Expected, 120))
+			fmt.Fprintf(w, "       Claimed:    %s\n", truncate(check.Actual, 120))
+		}
+	}
+
+	if check := result.Check(verify.CheckSeedStrength); check != nil {
+		fmt.Fprintln(w, "🔐 Verifying Server Seed Strength...")
+		if check.Skipped {
+			printSkippedCheck(w, check)
+		} else if check.Passed {
+			fmt.Fprintf(w, "    ✅ Server seed entropy %s\n", check.Actual)
+		} else {
+			fmt.Fprintf(w, "    ❌ Weak server seed: %s\n", check.Error)
+			fmt.Fprintf(w, "       Required: %s\n", check.Expected)
+		}
+	}
+
+	if check := result.Check(verify.CheckExpectedWinner); check != nil {
+		fmt.Fprintln(w, "📌 Verifying Expected Winner...")
+		if check.Skipped {
+			printSkippedCheck(w, check)
+		} else if check.Passed {
+			fmt.Fprintf(w, "    ✅ Round was won by the expected player: %s\n", check.Expected)
+		} else {
+			fmt.Fprintf(w, "    ❌ Unexpected winner!\n")
+			fmt.Fprintf(w, "       Expected:   %s\n", check.Expected)
+			fmt.Fprintf(w, "       Calculated: %s\n", check.Actual)
+		}
+	}
+
+	fmt.Fprintln(w, "5️⃣  Winner Ranges:")
+	if data.Cancelled {
+		fmt.Fprintln(w, "    ➖ N/A — round cancelled")
+	} else if len(data.Bets) == 0 && result.Partial {
+		fmt.Fprintln(w, "    ➖ N/A — bets not provided")
+	} else {
+		showRoundRanges(w, data, data.Result, result.WinnerRule, result.HouseAddresses)
+	}
+
+	if result.Trace != nil {
+		fmt.Fprintln(w, "6️⃣  Computation Trace:")
+		printTrace(w, result.Trace)
+	}
+
+	if result.Timings != nil {
+		fmt.Fprintln(w, "⏱️  Timings:")
+		printTimings(w, result.Timings)
+	}
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	failed := result.FailedChecks()
+	if result.Passed && result.Partial {
+		fmt.Fprintln(w, "🟡 PARTIALLY VERIFIED! Every check the published data allows passed.")
+		fmt.Fprintf(w, "    Could not check: %s\n", strings.Join(result.SkippedChecks(), ", "))
+	} else if result.Passed && result.Cancelled {
+		fmt.Fprintln(w, "🎉 VERIFICATION PASSED! Round cancelled — integrity verified, no winner expected.")
+	} else if result.Passed {
+		fmt.Fprintln(w, "🎉 VERIFICATION PASSED! This round is provably fair.")
+	} else if len(failed) == 1 && failed[0] == verify.CheckExpectedWinner {
+		fmt.Fprintln(w, "💀 VERIFICATION FAILED! The round is consistent but was not won by the expected player.")
+	} else {
+		fmt.Fprintln(w, "💀 VERIFICATION FAILED! This round may not be fair.")
+	}
+	if result.ShareURL != "" {
+		fmt.Fprintf(w, "🔗 Share this proof: %s\n", result.ShareURL)
+	}
+}
+
+// printRangeAssertion states the boundary convention and whether the result
+// lies in the claimed winner's range
+func printRangeAssertion(w io.Writer, r *verify.RangeAssertion) {
+	fmt.Fprintln(w, "    📐 Ranges are half-open [start, end): a result on a boundary belongs to the higher range")
+	switch {
+	case r.Contains && r.Result == r.End:
+		fmt.Fprintf(w, "    ✅ Result %.3f is the top of the domain, held by the last range [%.3f, %.3f] of claimed winner %s\n",
+			r.Result, r.Start, r.End, shortAddress(r.Player))
+	case r.Contains:
+		fmt.Fprintf(w, "    ✅ Result %.3f lies in [%.3f, %.3f) of claimed winner %s\n", r.Result, r.Start, r.End, shortAddress(r.Player))
+	case r.UpperBoundary:
+		fmt.Fprintf(w, "    ⚠️  Result %.3f is the excluded upper bound of claimed winner %s's range [%.3f, %.3f)\n",
+			r.Result, shortAddress(r.Player), r.Start, r.End)
+	default:
+		fmt.Fprintf(w, "    ❌ Result %.3f is outside [%.3f, %.3f) of claimed winner %s\n", r.Result, r.Start, r.End, shortAddress(r.Player))
+	}
+}
+
+// printDerivation shows the modular arithmetic behind the result with a
+// one-liner anyone can use to reproduce it
+func printDerivation(w io.Writer, d *verify.ResultDerivation) {
+	fmt.Fprintln(w, "    🔢 Result derivation:")
+	fmt.Fprintf(w, "       HMAC (hex):     %s\n", d.HMACHex)
+	fmt.Fprintf(w, "       HMAC (dec):     %s\n", d.HMACDecimal)
+	fmt.Fprintf(w, "       Modulus:        %d\n", d.Modulus)
+	fmt.Fprintf(w, "       HMAC mod %d = %d\n", d.Modulus, d.Remainder)
+	fmt.Fprintf(w, "       %d / %g = %.3f\n", d.Remainder, d.Divisor, d.Result)
+	fmt.Fprintf(w, "       Reproduce: python3 -c 'print(int(\"%s\", 16) %% %d)'\n", d.HMACHex, d.Modulus)
+}
+
+func printSkippedCheck(w io.Writer, check *verify.Check) {
+	fmt.Fprintf(w, "    ➖ N/A — %s\n", check.Error)
+}
+
+// formatOneline renders a result as a single greppable key=value line
+func formatOneline(result *verify.Report) string {
+	verified := fmt.Sprint(result.Passed)
+	if result.Passed && result.Partial {
+		verified = "partial"
+	}
+	return fmt.Sprintf("round=%d pot=%.2f result=%.3f winner=%s verified=%s",
+		result.RoundNumber, result.TotalPot, result.ClaimedResult, shortAddress(result.ClaimedWinner), verified)
+}
+
+// reportAPIError explains that the API could not produce verification data,
+// which is distinct from a round failing verification
+func reportAPIError(data verify.RoundVerificationData, asJSON bool) {
+	message := data.Error
+	if message == "" {
+		message = "no error message provided"
+	}
+	if asJSON {
+		writeJSON(struct {
+			RoundID string `json:"round_id,omitempty"`
+			Status  string `json:"status"`
+			Error   string `json:"error"`
+		}{data.RoundID, StatusSkipped, message})
+		return
+	}
+	fmt.Printf("⚠️  API returned an error for this round: %s\n", message)
+	fmt.Println("    Nothing was verified; this is not a verification failure.")
+}
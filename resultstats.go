@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/lazyton/jackpot-verification/verify"
+)
+
+// roundValueSteps are the round-number granularities --result-stats watches,
+// coarsest first: results such as 0.000, 50.000 or 100.000, then multiples
+// of 10, then whole numbers
+var roundValueSteps = []float64{50, 10, 1}
+
+// roundValueZScore is how far above its expected count a round-number class
+// must lie, in standard deviations, to be flagged
+const roundValueZScore = 3
+
+// RoundValueCount compares how often results were a multiple of Step with
+// how often a uniform result would be
+type RoundValueCount struct {
+	Step     float64 `json:"step"`
+	Observed int     `json:"observed"`
+	Expected float64 `json:"expected"`
+	// ZScore is how many standard deviations Observed lies above Expected
+	ZScore  float64 `json:"z_score"`
+	Flagged bool    `json:"flagged"`
+}
+
+// ResultStats is the distribution of a batch's claimed results. It never
+// fails verification: over-represented round numbers are a lead for an
+// auditor, such as a hardcoded or broken RNG, not proof of one.
+type ResultStats struct {
+	Rounds int `json:"rounds"`
+	// Deciles counts results in [0, 10), [10, 20) ... [90, 100]
+	Deciles     [10]int           `json:"deciles"`
+	RoundValues []RoundValueCount `json:"round_values"`
+	Flagged     bool              `json:"flagged"`
+}
+
+// resultStats tallies the claimed results of rounds. The chance of a round
+// value follows from the game's modulus and divisor: a result is k / divisor
+// for k uniform in [0, modulus).
+func resultStats(rounds []verify.RoundVerificationData, game verify.GameConfig) *ResultStats {
+	stats := &ResultStats{Rounds: len(rounds), RoundValues: []RoundValueCount{}}
+	for _, data := range rounds {
+		decile := int(data.Result / 10)
+		if decile < 0 {
+			decile = 0
+		} else if decile > 9 {
+			decile = 9
+		}
+		stats.Deciles[decile]++
+	}
+
+	n := float64(len(rounds))
+	for _, step := range roundValueSteps {
+		unit := int64(math.Round(step * game.Divisor))
+		if unit <= 0 || game.Modulus <= 0 {
+			continue
+		}
+		count := RoundValueCount{Step: step}
+		for _, data := range rounds {
+			if int64(math.Round(data.Result*game.Divisor))%unit == 0 {
+				count.Observed++
+			}
+		}
+		p := float64((game.Modulus-1)/unit+1) / float64(game.Modulus)
+		count.Expected = n * p
+		if sd := math.Sqrt(n * p * (1 - p)); sd > 0 {
+			count.ZScore = (float64(count.Observed) - count.Expected) / sd
+		}
+		count.Flagged = count.Observed >= 2 && count.ZScore >= roundValueZScore
+		stats.Flagged = stats.Flagged || count.Flagged
+		stats.RoundValues = append(stats.RoundValues, count)
+	}
+	return stats
+}
+
+func printResultStats(stats *ResultStats) {
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Printf("📊 Result Distribution (%d rounds):\n", stats.Rounds)
+	if stats.Rounds == 0 {
+		fmt.Println("    No rounds to tally")
+		return
+	}
+	most := 0
+	for _, n := range stats.Deciles {
+		if n > most {
+			most = n
+		}
+	}
+	for i, n := range stats.Deciles {
+		bar := strings.Repeat("█", (n*30+most-1)/most)
+		closing := ")"
+		if i == len(stats.Deciles)-1 {
+			closing = "]"
+		}
+		fmt.Printf("    [%2d, %3d%s %-30s %d\n", i*10, i*10+10, closing, bar, n)
+	}
+	for _, count := range stats.RoundValues {
+		icon := "✅"
+		if count.Flagged {
+			icon = "🚩"
+		}
+		fmt.Printf("    %s Multiples of %g: %d observed, %.2g expected\n", icon, count.Step, count.Observed, count.Expected)
+	}
+	if stats.Flagged {
+		fmt.Println("    🚩 Round-number results are over-represented: check the RNG for a hardcoded or")
+		fmt.Println("       truncated value. This does not fail verification by itself.")
+	}
+}
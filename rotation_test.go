@@ -0,0 +1,84 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/lazyton/jackpot-verification/verify"
+)
+
+// rotationRecord signs a valid old tip and a new genesis linked to it with a
+// fresh key, returning the record and the key's hex
+func rotationRecord(t *testing.T) (RotationRecord, string) {
+	t.Helper()
+	var old verify.RoundVerificationData
+	if err := json.Unmarshal([]byte(prettyRound), &old); err != nil {
+		t.Fatal(err)
+	}
+
+	game := verify.GameRegistry[verify.DefaultGameName]
+	genesis := old
+	genesis.RoundID, genesis.RoundNumber = "round-2", 2
+	genesis.ServerSeed = "5e1d9c7a3b2f8e4d6c0a9b1e7f3d5c8a2b4e6d0f1a3c5e7b9d2f4a6c8e0b1d3f"
+	genesis.ServerHash = verify.HashString(genesis.ServerSeed)
+	genesis.PreviousHash = old.ServerHash
+	genesis.Result = verify.CalculateResultTrace(game, genesis.ServerSeed, genesis.ClientSeed, genesis.RoundNumber, genesis.PreviousHash, nil)
+	winner, err := verify.SelectRoundWinner(game, genesis, genesis.Result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	genesis.WinnerAddress = winner
+
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := hex.EncodeToString(pub)
+	return RotationRecord{
+		OldTip:     old,
+		NewGenesis: genesis,
+		Signature:  hex.EncodeToString(ed25519.Sign(priv, []byte(old.ServerHash))),
+		PublicKey:  key,
+	}, key
+}
+
+func TestVerifyRotationKeyPinning(t *testing.T) {
+	record, key := rotationRecord(t)
+	otherKey, _, _ := ed25519.GenerateKey(nil)
+
+	tests := []struct {
+		name       string
+		trustedKey string
+		mutate     func(*RotationRecord)
+		passed     bool
+		unpinned   bool
+		exitCode   int
+	}{
+		{name: "pinned key", trustedKey: key, passed: true},
+		{name: "no pinned key", unpinned: true, exitCode: exitUnverifiable},
+		{name: "pinned key differs", trustedKey: hex.EncodeToString(otherKey), exitCode: exitFailed},
+		{name: "record signed by another key", mutate: func(r *RotationRecord) {
+			r.PublicKey = hex.EncodeToString(otherKey)
+		}, exitCode: exitFailed},
+		{name: "no pinned key and a broken link", mutate: func(r *RotationRecord) {
+			r.NewGenesis.PreviousHash = r.NewGenesis.ServerHash
+		}, unpinned: true, exitCode: exitFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := record
+			if tt.mutate != nil {
+				tt.mutate(&record)
+			}
+			result := verifyRotation(record, tt.trustedKey, verify.Options{Game: verify.GameRegistry[verify.DefaultGameName]})
+			if result.Passed != tt.passed || result.Unpinned != tt.unpinned {
+				t.Fatalf("passed=%v unpinned=%v, want %v and %v (checks %+v)", result.Passed, result.Unpinned, tt.passed, tt.unpinned, result.Checks)
+			}
+			if !tt.passed && result.exitCode() != tt.exitCode {
+				t.Errorf("exit code %d, want %d", result.exitCode(), tt.exitCode)
+			}
+		})
+	}
+}
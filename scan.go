@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// ScanReport summarizes a set of round files without verifying them
+type ScanReport struct {
+	Files      int     `json:"files"`
+	Rounds     int     `json:"rounds"`
+	Skipped    int     `json:"skipped"`
+	Unreadable int     `json:"unreadable"`
+	TotalBets  int     `json:"total_bets"`
+	TotalPot   float64 `json:"total_pot"`
+	FirstRound int     `json:"first_round,omitempty"`
+	LastRound  int     `json:"last_round,omitempty"`
+	// Earliest and Latest span the rounds' created_at timestamps; they are
+	// nil when no round carries a parseable one
+	Earliest *time.Time `json:"earliest,omitempty"`
+	Latest   *time.Time `json:"latest,omitempty"`
+	// Errors lists the files that could not be parsed
+	Errors []string `json:"errors,omitempty"`
+}
+
+// scanSources parses every source and aggregates its metadata. No hashes
+// are computed, so this is a quick overview before a full audit.
+func scanSources(sources []batchSource) *ScanReport {
+	report := &ScanReport{Files: len(sources)}
+	for _, source := range sources {
+		data, err := source.load()
+		if err != nil {
+			report.Unreadable++
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", source.Name, err))
+			continue
+		}
+		if !data.Success {
+			report.Skipped++
+			continue
+		}
+
+		report.Rounds++
+		report.TotalBets += len(data.Bets)
+		report.TotalPot += data.TotalPot
+		if report.Rounds == 1 || data.RoundNumber < report.FirstRound {
+			report.FirstRound = data.RoundNumber
+		}
+		if data.RoundNumber > report.LastRound {
+			report.LastRound = data.RoundNumber
+		}
+
+		created, err := time.Parse(time.RFC3339, data.CreatedAt)
+		if err != nil {
+			continue
+		}
+		if report.Earliest == nil || created.Before(*report.Earliest) {
+			report.Earliest = &created
+		}
+		if report.Latest == nil || created.After(*report.Latest) {
+			report.Latest = &created
+		}
+	}
+	return report
+}
+
+// printScanReport prints the aggregates of a scan
+func printScanReport(report *ScanReport) {
+	fmt.Printf("🔎 Scanned %d file(s)\n", report.Files)
+	fmt.Printf("    🎰 Rounds:     %d", report.Rounds)
+	if report.Rounds > 0 {
+		fmt.Printf(" (#%d – #%d)", report.FirstRound, report.LastRound)
+	}
+	fmt.Println()
+	fmt.Printf("    🎟️  Bets:       %d\n", report.TotalBets)
+	fmt.Printf("    📊 Total pot:  %.2f TON\n", report.TotalPot)
+	if report.Earliest != nil {
+		fmt.Printf("    📅 Dates:      %s – %s\n",
+			report.Earliest.Format(time.RFC3339), report.Latest.Format(time.RFC3339))
+	} else {
+		fmt.Println("    📅 Dates:      unknown (no created_at timestamps)")
+	}
+	fmt.Printf("    ⚠️  Skipped:    %d\n", report.Skipped)
+	if report.Unreadable > 0 {
+		fmt.Printf("    ❌ Unreadable: %d\n", report.Unreadable)
+		for _, e := range report.Errors {
+			fmt.Printf("       %s\n", e)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/lazyton/jackpot-verification/verify"
+)
+
+// maxRequestBytes is the default bound on the round data accepted by --serve
+const maxRequestBytes = 10 << 20
+
+// errResourceLimit prefixes the response to a request that exceeded one of
+// the serveLimits
+const errResourceLimit = "resource limit exceeded"
+
+// serveOptions controls the HTTP verification endpoint
+type serveOptions struct {
+	Addr   string
+	Verify verify.Options
+	// SafeErrors reduces responses to the round id, pass status and the
+	// names of failed checks, so a public endpoint never echoes seeds,
+	// hashes or parse details back to the caller
+	SafeErrors bool
+	// Limits bound the work a single request may cause
+	Limits serveLimits
+	// Drand lists the relays each request's beacon_round is fetched from,
+	// as --drand does for a single round; DrandTimeout bounds each fetch
+	Drand        []string
+	DrandTimeout time.Duration
+}
+
+// serveLimits caps what one request may consume, so a hostile payload cannot
+// tie up the endpoint. Zero disables a limit.
+type serveLimits struct {
+	// MaxBytes bounds the request body
+	MaxBytes int64
+	// MaxBets bounds the number of bets in the round
+	MaxBets int
+	// Timeout bounds how long a verification may run. It is enforced between
+	// checks, and the response is sent as soon as it expires.
+	Timeout time.Duration
+}
+
+// safeResult is the --safe-errors response for a verified round
+type safeResult struct {
+	RoundID         string `json:"round_id"`
+	Passed          bool   `json:"passed"`
+	Partial         bool   `json:"partial,omitempty"`
+	Error           string `json:"error,omitempty"`
+	VerifierVersion string `json:"verifier_version"`
+}
+
+// newServeHandler returns the handler for --serve: POST /verify takes round
+// data in the same JSON format as the CLI and returns the result as JSON.
+// Every check always runs, so response time does not reveal which check
+// failed first.
+func newServeHandler(opts serveOptions) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/verify", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeHTTPError(w, http.StatusMethodNotAllowed, "use POST")
+			return
+		}
+
+		body := r.Body
+		if opts.Limits.MaxBytes > 0 {
+			body = http.MaxBytesReader(w, r.Body, opts.Limits.MaxBytes)
+		}
+		raw, err := io.ReadAll(body)
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				writeLimitError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body over %d bytes", tooLarge.Limit))
+			} else {
+				writeHTTPError(w, http.StatusBadRequest, "failed to read request body")
+			}
+			return
+		}
+		var data verify.RoundVerificationData
+		began := time.Now()
+		if err := json.Unmarshal(normalizeInput(raw), &data); err != nil {
+			message := "invalid round data"
+			if !opts.SafeErrors {
+				message += ": " + err.Error()
+			}
+			writeHTTPError(w, http.StatusBadRequest, message)
+			return
+		}
+		parseTime := time.Since(began)
+		if !data.Success {
+			message := "round data reports an API error"
+			if !opts.SafeErrors && data.Error != "" {
+				message += ": " + data.Error
+			}
+			writeHTTPError(w, http.StatusUnprocessableEntity, message)
+			return
+		}
+
+		if max := opts.Limits.MaxBets; max > 0 && len(data.Bets) > max {
+			writeLimitError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("%d bets, more than %d", len(data.Bets), max))
+			return
+		}
+
+		verifyOpts := opts.Verify
+		if len(opts.Drand) > 0 && data.BeaconRound != 0 {
+			beacons, err := fetchDrandBeacons(r.Context(), opts.Drand, data.BeaconRound, opts.DrandTimeout)
+			if err != nil {
+				message := "failed to fetch the drand beacon"
+				if !opts.SafeErrors {
+					message += fmt.Sprintf(" round %d: %v", data.BeaconRound, err)
+				}
+				writeHTTPError(w, http.StatusBadGateway, message)
+				return
+			}
+			verifyOpts.Beacons = beacons
+		}
+
+		result, err := verifyWithDeadline(r.Context(), data, verifyOpts, opts.Limits.Timeout)
+		if err != nil {
+			writeLimitError(w, http.StatusServiceUnavailable, fmt.Sprintf("verification took longer than %s", opts.Limits.Timeout))
+			return
+		}
+		if result.Timings != nil {
+			result.Timings.ParseMS = verify.DurationMS(parseTime)
+		}
+		if opts.SafeErrors {
+			writeHTTPJSON(w, http.StatusOK, safeResponse(result))
+			return
+		}
+		writeHTTPJSON(w, http.StatusOK, result)
+	})
+	return mux
+}
+
+// verifyWithDeadline verifies data, giving up once timeout has passed. The
+// remaining checks are then aborted; one already running finishes in the
+// background, but its result is discarded.
+func verifyWithDeadline(ctx context.Context, data verify.RoundVerificationData, opts verify.Options, timeout time.Duration) (*verify.Report, error) {
+	if timeout <= 0 {
+		return verify.VerifyRoundWithOptions(data, opts), nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	abort := opts.Hooks.Abort
+	opts.Hooks.Abort = func(check verify.Check) bool {
+		return ctx.Err() != nil || (abort != nil && abort(check))
+	}
+	done := make(chan *verify.Report, 1)
+	go func() { done <- verify.VerifyRoundWithOptions(data, opts) }()
+
+	select {
+	case result := <-done:
+		if result.Aborted && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return result, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// safeResponse strips a result down to what --safe-errors may disclose
+func safeResponse(result *verify.Report) safeResult {
+	safe := safeResult{
+		RoundID:         result.RoundID,
+		Passed:          result.Passed,
+		Partial:         result.Partial,
+		VerifierVersion: result.VerifierVersion,
+	}
+	if failed := result.FailedChecks(); len(failed) > 0 {
+		safe.Error = "verification failed: " + strings.Join(failed, ", ")
+	}
+	return safe
+}
+
+func writeHTTPJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Failed to write response: %v", err)
+	}
+}
+
+func writeHTTPError(w http.ResponseWriter, status int, message string) {
+	writeHTTPJSON(w, status, struct {
+		Error string `json:"error"`
+	}{message})
+}
+
+// writeLimitError rejects a request that exceeded a serveLimits bound
+func writeLimitError(w http.ResponseWriter, status int, detail string) {
+	writeHTTPError(w, status, errResourceLimit+": "+detail)
+}
+
+// serve runs the verification endpoint until ctx is cancelled
+func serve(ctx context.Context, opts serveOptions) error {
+	server := &http.Server{
+		Addr:              opts.Addr,
+		Handler:           newServeHandler(opts),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errc := make(chan error, 1)
+	go func() { errc <- server.ListenAndServe() }()
+	log.Printf("Serving verification on %s (POST /verify)", opts.Addr)
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+	shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdown); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lazyton/jackpot-verification/verify"
+)
+
+func TestServeHandler(t *testing.T) {
+	defaults := verify.Options{Game: verify.GameRegistry[verify.DefaultGameName]}
+	failing := strings.Replace(prettyRound, `"result": 54.898`, `"result": 12.345`, 1)
+	slow := defaults
+	slow.Hooks.OnCheckComplete = func(string, bool, interface{}, interface{}) { time.Sleep(20 * time.Millisecond) }
+
+	tests := []struct {
+		name       string
+		opts       serveOptions
+		method     string
+		body       string
+		wantStatus int
+		// wantBody must appear in the response; hidden must not
+		wantBody string
+		hidden   []string
+	}{
+		{name: "verified round", opts: serveOptions{Verify: defaults}, body: prettyRound, wantStatus: http.StatusOK, wantBody: `"passed":true`},
+		{name: "wrong method", opts: serveOptions{Verify: defaults}, method: http.MethodGet, wantStatus: http.StatusMethodNotAllowed, wantBody: "use POST"},
+		{
+			name: "full failure detail", opts: serveOptions{Verify: defaults}, body: failing,
+			wantStatus: http.StatusOK, wantBody: `"actual":"12.345"`,
+		},
+		{
+			name: "safe failure", opts: serveOptions{Verify: defaults, SafeErrors: true}, body: failing,
+			wantStatus: http.StatusOK, wantBody: `"error":"verification failed: result`,
+			hidden: []string{"e72430b6bf09ac29", "EQB2bbbbbbbb5C3D", "12.345"},
+		},
+		{
+			name: "parse error detail", opts: serveOptions{Verify: defaults}, body: `{"success": tru`,
+			wantStatus: http.StatusBadRequest, wantBody: "invalid round data: ",
+		},
+		{
+			name: "safe parse error", opts: serveOptions{Verify: defaults, SafeErrors: true}, body: `{"success": tru`,
+			wantStatus: http.StatusBadRequest, wantBody: `{"error":"invalid round data"}`, hidden: []string{"tru"},
+		},
+		{
+			name: "safe API error", opts: serveOptions{Verify: defaults, SafeErrors: true}, body: `{"success": false, "error": "secret backend detail"}`,
+			wantStatus: http.StatusUnprocessableEntity, wantBody: `{"error":"round data reports an API error"}`, hidden: []string{"secret"},
+		},
+		{
+			name: "body over the size limit", opts: serveOptions{Verify: defaults, Limits: serveLimits{MaxBytes: 64}}, body: prettyRound,
+			wantStatus: http.StatusRequestEntityTooLarge, wantBody: "resource limit exceeded: request body over 64 bytes",
+		},
+		{
+			name: "bets over the limit", opts: serveOptions{Verify: defaults, Limits: serveLimits{MaxBets: 2}}, body: prettyRound,
+			wantStatus: http.StatusRequestEntityTooLarge, wantBody: "resource limit exceeded: 3 bets, more than 2",
+		},
+		{
+			name: "bets at the limit", opts: serveOptions{Verify: defaults, Limits: serveLimits{MaxBets: 3}}, body: prettyRound,
+			wantStatus: http.StatusOK, wantBody: `"passed":true`,
+		},
+		{
+			name: "verification over the timeout", opts: serveOptions{Verify: slow, Limits: serveLimits{Timeout: 10 * time.Millisecond}}, body: prettyRound,
+			wantStatus: http.StatusServiceUnavailable, wantBody: "resource limit exceeded: verification took longer than 10ms",
+		},
+		{
+			name: "verification within the timeout", opts: serveOptions{Verify: defaults, Limits: serveLimits{Timeout: time.Minute}}, body: prettyRound,
+			wantStatus: http.StatusOK, wantBody: `"passed":true`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = http.MethodPost
+			}
+			rec := httptest.NewRecorder()
+			newServeHandler(tt.opts).ServeHTTP(rec, httptest.NewRequest(method, "/verify", strings.NewReader(tt.body)))
+
+			body := rec.Body.String()
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status %d, want %d: %s", rec.Code, tt.wantStatus, body)
+			}
+			if !json.Valid(rec.Body.Bytes()) {
+				t.Errorf("response is not JSON: %s", body)
+			}
+			if !strings.Contains(body, tt.wantBody) {
+				t.Errorf("response %s lacks %q", body, tt.wantBody)
+			}
+			for _, secret := range tt.hidden {
+				if strings.Contains(body, secret) {
+					t.Errorf("response %s discloses %q", body, secret)
+				}
+			}
+		})
+	}
+}
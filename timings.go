@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/lazyton/jackpot-verification/verify"
+)
+
+// printTimings lists the steps of a verification, slowest first
+func printTimings(w io.Writer, t *verify.Timings) {
+	names := make([]string, 0, len(t.StepsMS))
+	for name := range t.StepsMS {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return t.StepsMS[names[i]] > t.StepsMS[names[j]]
+	})
+
+	if t.ParseMS > 0 {
+		fmt.Fprintf(w, "    parse             %8.3f ms\n", t.ParseMS)
+	}
+	for _, name := range names {
+		fmt.Fprintf(w, "    %-17s %8.3f ms\n", name, t.StepsMS[name])
+	}
+	fmt.Fprintf(w, "    verification      %8.3f ms (excluding parse)\n", t.TotalMS)
+	fmt.Fprintf(w, "    hash ops: %d commitment, %d client seed bytes, %d HMAC, %d ranges scanned\n",
+		t.HashOps.CommitHashes, t.HashOps.ClientSeedBytes, t.HashOps.HMACs, t.HashOps.RangesScanned)
+}
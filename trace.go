@@ -0,0 +1,19 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/lazyton/jackpot-verification/verify"
+)
+
+func printTrace(w io.Writer, t *verify.Trace) {
+	if t == nil || len(t.Steps) == 0 {
+		fmt.Fprintln(w, "    No trace recorded")
+		return
+	}
+
+	for i, step := range t.Steps {
+		fmt.Fprintf(w, "    %03d %s = %s\n", i+1, step.Label, step.Value)
+	}
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lazyton/jackpot-verification/verify"
+)
+
+const (
+	// defaultTONAPIURL is the toncenter v3 index used by --check-txs
+	defaultTONAPIURL = "https://toncenter.com/api/v3"
+	// tonAPIKeyEnv holds an optional toncenter API key
+	tonAPIKeyEnv = "TON_API_KEY"
+	// nanotonsPerTON converts on-chain values to TON
+	nanotonsPerTON = 1e9
+	// maxTransactionBytes bounds a transaction lookup response
+	maxTransactionBytes = 1 << 20
+)
+
+// BetTransaction is the on-chain check of one bet's payment
+type BetTransaction struct {
+	Player string  `json:"player"`
+	Amount float64 `json:"amount"`
+	TxHash string  `json:"tx_hash"`
+	// Transferred is the TON value the transaction carried, when found
+	Transferred float64 `json:"transferred,omitempty"`
+	Passed      bool    `json:"passed"`
+	Problem     string  `json:"problem,omitempty"`
+}
+
+// TransactionReport is the outcome of --check-txs
+type TransactionReport struct {
+	RoundID        string           `json:"round_id"`
+	OperatorWallet string           `json:"operator_wallet"`
+	Passed         bool             `json:"passed"`
+	Bets           []BetTransaction `json:"bets"`
+
+	VerifierVersion string `json:"verifier_version"`
+}
+
+// tonTransaction is the part of a toncenter v3 transaction the check needs.
+// Transactions served by the index are already committed to the masterchain.
+type tonTransaction struct {
+	Hash    string `json:"hash"`
+	Account string `json:"account"`
+	InMsg   *struct {
+		Source      string `json:"source"`
+		Destination string `json:"destination"`
+		Value       string `json:"value"`
+	} `json:"in_msg"`
+	Description struct {
+		Aborted bool `json:"aborted"`
+	} `json:"description"`
+}
+
+// txOptions controls how bet transactions are looked up
+type txOptions struct {
+	APIURL         string
+	OperatorWallet string
+	Timeout        time.Duration
+}
+
+// checkBetTransactions confirms that every bet carries the hash of a
+// committed, non-aborted transaction transferring at least its amount from
+// the player to the operator wallet. Bets without a tx_hash fail: they are
+// not backed by a payment the verifier can see. Nor can one payment back
+// several bets, so every bet sharing a tx_hash with another fails too.
+func checkBetTransactions(ctx context.Context, data verify.RoundVerificationData, opts txOptions) *TransactionReport {
+	report := &TransactionReport{
+		RoundID:         data.RoundID,
+		OperatorWallet:  opts.OperatorWallet,
+		Passed:          true,
+		Bets:            []BetTransaction{},
+		VerifierVersion: verify.VersionString(),
+	}
+	uses := make(map[string][]int)
+	for i, bet := range data.Bets {
+		if bet.TxHash != "" {
+			key := txHashKey(bet.TxHash)
+			uses[key] = append(uses[key], i+1)
+		}
+	}
+	for i, bet := range data.Bets {
+		check := BetTransaction{Player: bet.PlayerAddress, Amount: bet.Amount, TxHash: bet.TxHash}
+		if bet.TxHash == "" {
+			check.Problem = "no tx_hash"
+		} else if others := otherBets(uses[txHashKey(bet.TxHash)], i+1); others != "" {
+			check.Problem = "tx_hash is reused by bet " + others
+		} else if tx, err := fetchTransaction(ctx, opts, bet.TxHash); err != nil {
+			check.Problem = "lookup failed: " + err.Error()
+		} else if tx == nil {
+			check.Problem = "transaction not found on chain"
+		} else {
+			check.Transferred, check.Problem = matchBetTransaction(bet, tx, opts.OperatorWallet)
+		}
+		check.Passed = check.Problem == ""
+		if !check.Passed {
+			report.Passed = false
+		}
+		report.Bets = append(report.Bets, check)
+	}
+	return report
+}
+
+// otherBets lists the bet numbers in bets other than self, or "" if none
+func otherBets(bets []int, self int) string {
+	var others []string
+	for _, n := range bets {
+		if n != self {
+			others = append(others, "#"+strconv.Itoa(n))
+		}
+	}
+	return strings.Join(others, ", ")
+}
+
+// txHashKey returns the lowercase hex of a transaction hash given in hex or
+// base64, so one hash written two ways is still recognized as reused
+func txHashKey(hash string) string {
+	if decoded, err := hex.DecodeString(hash); err == nil && len(decoded) == 32 {
+		return hex.EncodeToString(decoded)
+	}
+	normalized := strings.NewReplacer("-", "+", "_", "/").Replace(hash)
+	if decoded, err := base64.StdEncoding.DecodeString(normalized); err == nil && len(decoded) == 32 {
+		return hex.EncodeToString(decoded)
+	}
+	return hash
+}
+
+// matchBetTransaction returns the TON value of tx and what, if anything,
+// keeps it from paying for bet
+func matchBetTransaction(bet verify.VerificationBet, tx *tonTransaction, operator string) (float64, string) {
+	if tx.InMsg == nil {
+		return 0, "transaction has no incoming transfer"
+	}
+	nanotons, err := strconv.ParseInt(tx.InMsg.Value, 10, 64)
+	if err != nil {
+		return 0, fmt.Sprintf("invalid transfer value %q", tx.InMsg.Value)
+	}
+	transferred := float64(nanotons) / nanotonsPerTON
+
+	switch {
+	case tx.Description.Aborted:
+		return transferred, "transaction was aborted"
+	case !sameTONAddress(tx.InMsg.Source, bet.PlayerAddress):
+		return transferred, fmt.Sprintf("sent by %s, not the player", tx.InMsg.Source)
+	case !sameTONAddress(tx.InMsg.Destination, operator):
+		return transferred, fmt.Sprintf("sent to %s, not the operator wallet", tx.InMsg.Destination)
+	case transferred < bet.Amount-verify.PotTolerance:
+		return transferred, fmt.Sprintf("transferred %.3f TON, less than the bet", transferred)
+	}
+	return transferred, ""
+}
+
+// fetchTransaction looks a transaction up by hash, returning nil when the
+// index does not know it
+func fetchTransaction(ctx context.Context, opts txOptions, hash string) (*tonTransaction, error) {
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	endpoint := strings.TrimRight(opts.APIURL, "/") + "/transactions?" + url.Values{"hash": {hash}}.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if key := os.Getenv(tonAPIKeyEnv); key != "" {
+		req.Header.Set("X-API-Key", key)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxTransactionBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, truncate(string(raw), 200))
+	}
+
+	var page struct {
+		Transactions []tonTransaction `json:"transactions"`
+	}
+	if err := json.Unmarshal(raw, &page); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %v", err)
+	}
+	if len(page.Transactions) == 0 {
+		return nil, nil
+	}
+	return &page.Transactions[0], nil
+}
+
+// sameTONAddress compares two TON addresses in any mix of raw
+// ("0:<hex>") and user-friendly (base64, bounceable or not) forms
+func sameTONAddress(a, b string) bool {
+	rawA, errA := rawTONAddress(a)
+	rawB, errB := rawTONAddress(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return rawA == rawB
+}
+
+// rawTONAddress converts an address to the raw "<workchain>:<hex>" form
+func rawTONAddress(address string) (string, error) {
+	if workchain, account, ok := strings.Cut(address, ":"); ok {
+		if _, err := strconv.Atoi(workchain); err != nil {
+			return "", err
+		}
+		decoded, err := hex.DecodeString(account)
+		if err != nil || len(decoded) != 32 {
+			return "", fmt.Errorf("invalid raw address %q", address)
+		}
+		return workchain + ":" + hex.EncodeToString(decoded), nil
+	}
+
+	// User-friendly form: flags, workchain, 32-byte account, 2-byte CRC
+	normalized := strings.NewReplacer("-", "+", "_", "/").Replace(address)
+	decoded, err := base64.StdEncoding.DecodeString(normalized)
+	if err != nil || len(decoded) != 36 {
+		return "", fmt.Errorf("invalid user-friendly address %q", address)
+	}
+	workchain := int(int8(decoded[1]))
+	return strconv.Itoa(workchain) + ":" + hex.EncodeToString(decoded[2:34]), nil
+}
+
+func printTransactionReport(report *TransactionReport) {
+	fmt.Printf("⛓️  Checking Bet Transactions for round %s\n", report.RoundID)
+	fmt.Printf("💼 Operator wallet: %s\n", report.OperatorWallet)
+	fmt.Println(strings.Repeat("=", 60))
+	if len(report.Bets) == 0 {
+		fmt.Println("    No bets to check")
+	}
+	for _, bet := range report.Bets {
+		if bet.Passed {
+			fmt.Printf("    ✅ %s paid %.3f TON for a %.3f TON bet (tx %s)\n",
+				shortAddress(bet.Player), bet.Transferred, bet.Amount, verify.ShortHash(bet.TxHash))
+			continue
+		}
+		fmt.Printf("    ❌ %s bet %.3f TON: %s", shortAddress(bet.Player), bet.Amount, bet.Problem)
+		if bet.TxHash != "" {
+			fmt.Printf(" (tx %s)", verify.ShortHash(bet.TxHash))
+		}
+		fmt.Println()
+	}
+
+	fmt.Println(strings.Repeat("=", 60))
+	if report.Passed {
+		fmt.Println("🎉 Every bet is backed by a confirmed on-chain payment.")
+	} else {
+		fmt.Println("💀 Some bets are not backed by a matching on-chain payment.")
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/lazyton/jackpot-verification/verify"
+)
+
+func TestCheckBetTransactions(t *testing.T) {
+	const (
+		playerA  = "0:" + "aa00000000000000000000000000000000000000000000000000000000000000"
+		playerB  = "0:" + "bb00000000000000000000000000000000000000000000000000000000000000"
+		operator = "0:" + "0e00000000000000000000000000000000000000000000000000000000000000"
+	)
+	hashA := strings.Repeat("a1", 32)
+	hashB := strings.Repeat("b2", 32)
+	rawB, _ := hex.DecodeString(hashB)
+
+	chain := map[string]tonTransaction{}
+	pay := func(hash, from, value string) {
+		tx := tonTransaction{Hash: hash, Account: operator}
+		tx.InMsg = &struct {
+			Source      string `json:"source"`
+			Destination string `json:"destination"`
+			Value       string `json:"value"`
+		}{Source: from, Destination: operator, Value: value}
+		chain[hash] = tx
+	}
+	pay(hashA, playerA, "2000000000")
+	pay(hashB, playerB, "5000000000")
+
+	var lookups int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&lookups, 1)
+		var page struct {
+			Transactions []tonTransaction `json:"transactions"`
+		}
+		if tx, ok := chain[r.URL.Query().Get("hash")]; ok {
+			page.Transactions = append(page.Transactions, tx)
+		}
+		json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+	opts := txOptions{APIURL: server.URL, OperatorWallet: operator}
+
+	tests := []struct {
+		name        string
+		bets        []verify.VerificationBet
+		wantProblem []string
+		wantLookups int32
+	}{
+		{
+			name: "distinct payments",
+			bets: []verify.VerificationBet{
+				{PlayerAddress: playerA, Amount: 2, TxHash: hashA},
+				{PlayerAddress: playerB, Amount: 5, TxHash: hashB},
+			},
+			wantProblem: []string{"", ""},
+			wantLookups: 2,
+		},
+		{
+			name: "one payment for two bets",
+			bets: []verify.VerificationBet{
+				{PlayerAddress: playerB, Amount: 2.5, TxHash: hashB},
+				{PlayerAddress: playerB, Amount: 2.5, TxHash: hashB},
+				{PlayerAddress: playerA, Amount: 2, TxHash: hashA},
+			},
+			wantProblem: []string{"tx_hash is reused by bet #2", "tx_hash is reused by bet #1", ""},
+			wantLookups: 1,
+		},
+		{
+			name: "reused in another encoding",
+			bets: []verify.VerificationBet{
+				{PlayerAddress: playerB, Amount: 2.5, TxHash: hashB},
+				{PlayerAddress: playerB, Amount: 2.5, TxHash: strings.ToUpper(hashB)},
+				{PlayerAddress: playerB, Amount: 2.5, TxHash: base64.URLEncoding.EncodeToString(rawB)},
+			},
+			wantProblem: []string{"tx_hash is reused by bet #2, #3", "tx_hash is reused by bet #1, #3", "tx_hash is reused by bet #1, #2"},
+		},
+		{
+			name: "missing and unknown",
+			bets: []verify.VerificationBet{
+				{PlayerAddress: playerA, Amount: 2},
+				{PlayerAddress: playerA, Amount: 2, TxHash: strings.Repeat("c3", 32)},
+			},
+			wantProblem: []string{"no tx_hash", "transaction not found on chain"},
+			wantLookups: 1,
+		},
+		{
+			name: "wrong sender and short payment",
+			bets: []verify.VerificationBet{
+				{PlayerAddress: playerB, Amount: 2, TxHash: hashA},
+				{PlayerAddress: playerB, Amount: 6, TxHash: hashB},
+			},
+			wantProblem: []string{"sent by " + playerA + ", not the player", "transferred 5.000 TON, less than the bet"},
+			wantLookups: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			atomic.StoreInt32(&lookups, 0)
+			data := verify.RoundVerificationData{RoundID: "r1", Bets: tt.bets}
+			report := checkBetTransactions(context.Background(), data, opts)
+
+			passed := true
+			for i, bet := range report.Bets {
+				if bet.Problem != tt.wantProblem[i] || bet.Passed != (tt.wantProblem[i] == "") {
+					t.Errorf("bet #%d: passed=%v problem %q, want %q", i+1, bet.Passed, bet.Problem, tt.wantProblem[i])
+				}
+				passed = passed && bet.Passed
+			}
+			if report.Passed != passed {
+				t.Errorf("report passed = %v with bet results %v", report.Passed, passed)
+			}
+			if got := atomic.LoadInt32(&lookups); got != tt.wantLookups {
+				t.Errorf("index saw %d lookups, want %d", got, tt.wantLookups)
+			}
+		})
+	}
+}
+
+func TestFetchTransactionCapsResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"transactions": [{"hash": "`))
+		w.Write([]byte(strings.Repeat("a", 2*maxTransactionBytes)))
+		w.Write([]byte(`"}]}`))
+	}))
+	defer server.Close()
+
+	_, err := fetchTransaction(context.Background(), txOptions{APIURL: server.URL}, "h")
+	if err == nil || !strings.Contains(err.Error(), "failed to parse response") {
+		t.Fatalf("error = %v, want the oversized response cut off and rejected", err)
+	}
+}
@@ -0,0 +1,45 @@
+package verify
+
+import (
+	"fmt"
+	"strings"
+)
+
+// betAmountsCheck flags bets larger than the whole pot or than maxBet
+// (when positive). Such a bet would dominate the ranges and almost always
+// means corrupt data or an attempt to skew the draw.
+func betAmountsCheck(data RoundVerificationData, maxBet float64) Check {
+	check := Check{
+		Name:     CheckBetAmounts,
+		Passed:   true,
+		Expected: fmt.Sprintf("every bet <= total pot %.3f", data.TotalPot),
+		Actual:   fmt.Sprintf("%d bets within limits", len(data.Bets)),
+	}
+	if maxBet > 0 {
+		check.Expected += fmt.Sprintf(" and <= cap %.3f", maxBet)
+	}
+
+	var problems []string
+	for _, bet := range data.Bets {
+		// Shares are percentages, not TON, and cannot be compared to the pot
+		if data.TotalPot > 0 && !data.AmountsAreShares && bet.Amount > data.TotalPot+PotTolerance {
+			problems = append(problems, fmt.Sprintf("bet by %s of %.3f TON exceeds the total pot of %.3f TON",
+				bet.PlayerAddress, bet.Amount, data.TotalPot))
+		}
+		if maxBet > 0 && bet.Amount > maxBet {
+			problems = append(problems, fmt.Sprintf("bet by %s of %.3f TON exceeds the cap of %.3f TON",
+				bet.PlayerAddress, bet.Amount, maxBet))
+		}
+	}
+
+	if len(problems) > 0 {
+		check.Passed = false
+		check.Actual = fmt.Sprintf("%d problem(s)", len(problems))
+		check.Error = strings.Join(problems, "; ")
+	}
+	return check
+}
+
+// PotTolerance absorbs float noise in TON amounts, which are hashed at three
+// decimal places
+const PotTolerance = 0.0005
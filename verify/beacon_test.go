@@ -0,0 +1,48 @@
+package verify
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBeaconCheck(t *testing.T) {
+	const signature = "8d61d9100567de44682506aea1a7a6fa6e5491cd27a0a0ed349ef6910ac5ac20ff7bc3e09d7c046566c9f7f3c6f3b10104990e7cb424998203d8f7de586fb7fa5f60045417a432684f85093b06ca91c769f0e7ca19268375e659c2a2352b4655"
+	value, err := beaconRandomness(signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	relay := func(round int64, randomness string) DrandBeacon {
+		return DrandBeacon{Relay: "https://relay.example", Round: round, Randomness: randomness}
+	}
+
+	tests := []struct {
+		name      string
+		signature string
+		relays    []DrandBeacon
+		wantError string // "" when the check passes
+	}{
+		{name: "confirmed by a relay", signature: signature, relays: []DrandBeacon{relay(42, value)}},
+		{name: "confirmed without a signature", relays: []DrandBeacon{relay(42, strings.ToUpper(value))}},
+		{name: "signature hash alone is unverified", signature: signature, wantError: "unverified: no --drand relay confirmed beacon round 42"},
+		{name: "nothing to check against", wantError: "unverified"},
+		{name: "relay disagrees", signature: signature, relays: []DrandBeacon{relay(42, value), relay(42, strings.Repeat("0", 64))}, wantError: "serves randomness 0000000000000000"},
+		{name: "relay serves another round", relays: []DrandBeacon{relay(41, value)}, wantError: "served round 41 instead of 42"},
+		{name: "signature does not hash to the value", signature: "00" + signature[2:], relays: []DrandBeacon{relay(42, value)}, wantError: "not the SHA-256"},
+		{name: "signature is not hex", signature: "zz", relays: []DrandBeacon{relay(42, value)}, wantError: "not valid hex"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := RoundVerificationData{BeaconRound: 42, BeaconValue: value, BeaconSignature: tt.signature}
+			check := beaconCheck(data, tt.relays)
+			if tt.wantError == "" {
+				if !check.Passed {
+					t.Fatalf("check failed: %s", check.Error)
+				}
+				return
+			}
+			if check.Passed || !strings.Contains(check.Error, tt.wantError) {
+				t.Fatalf("check = %+v, want a failure containing %q", check, tt.wantError)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package verify
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Kinds of chain-level findings
+const (
+	FindingGap           = "numbering_gap"
+	FindingDuplicate     = "duplicate_round"
+	FindingBrokenLink    = "broken_link"
+	FindingDuplicateSeed = "duplicate_seed"
+)
+
+// ChainFinding is a problem with how rounds fit together, as opposed to a
+// problem with any single round
+type ChainFinding struct {
+	Kind          string `json:"kind"`
+	Round         int    `json:"round"`
+	PreviousRound int    `json:"previous_round"`
+	Message       string `json:"message"`
+}
+
+// ChainReport is the outcome of verifying a sequence of rounds as a chain
+type ChainReport struct {
+	Passed     bool           `json:"passed"`
+	Rounds     int            `json:"rounds"`
+	FirstRound int            `json:"first_round"`
+	LastRound  int            `json:"last_round"`
+	Findings   []ChainFinding `json:"findings"`
+	// FailedRounds counts the rounds whose own verification failed
+	FailedRounds int `json:"failed_rounds,omitempty"`
+	// Results holds each round's own verification, in round number order.
+	// Only VerifyChain fills it in.
+	Results []*Report `json:"results,omitempty"`
+}
+
+// VerifyChain verifies every round with opts, as VerifyRoundWithOptions
+// does, and the rounds together as a chain, in one call. The report passes
+// only if every round passes and there are no chain findings.
+func VerifyChain(rounds []RoundVerificationData, opts Options) ChainReport {
+	var results []*Report
+	for _, data := range sortByRoundNumber(rounds) {
+		results = append(results, VerifyRoundWithOptions(data, opts))
+	}
+	report := CheckChainLinks(rounds, results)
+	report.Results = results
+	return report
+}
+
+// CheckChainLinks checks how rounds fit together without verifying them
+// again: results are the rounds' existing verifications, in any order, and
+// the report passes only if each of them passed and there are no chain
+// findings. The CLI's --chain mode passes the results of its batch.
+func CheckChainLinks(rounds []RoundVerificationData, results []*Report) ChainReport {
+	report := chainFindings(rounds)
+	for _, result := range results {
+		if !result.Passed {
+			report.FailedRounds++
+			report.Passed = false
+		}
+	}
+	return *report
+}
+
+// sortByRoundNumber returns a copy of rounds ordered by round number
+func sortByRoundNumber(rounds []RoundVerificationData) []RoundVerificationData {
+	sorted := make([]RoundVerificationData, len(rounds))
+	copy(sorted, rounds)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].RoundNumber < sorted[j].RoundNumber
+	})
+	return sorted
+}
+
+// chainFindings orders rounds by round number and reports every place where
+// they do not fit together: numbering that does not advance by exactly one
+// (a gap may mean a round was hidden from the public record even if every
+// visible round verifies), a round whose previous_hash is not the server
+// hash of the round before it, and a server seed used in more than one
+// round, which makes its draws predictable once revealed.
+func chainFindings(rounds []RoundVerificationData) *ChainReport {
+	sorted := sortByRoundNumber(rounds)
+
+	report := &ChainReport{Passed: true, Rounds: len(sorted), Findings: []ChainFinding{}}
+	if len(sorted) == 0 {
+		return report
+	}
+	report.FirstRound = sorted[0].RoundNumber
+	report.LastRound = sorted[len(sorted)-1].RoundNumber
+
+	for i := 1; i < len(sorted); i++ {
+		prev, cur := sorted[i-1].RoundNumber, sorted[i].RoundNumber
+		switch {
+		case cur == prev:
+			report.addFinding(ChainFinding{
+				Kind:          FindingDuplicate,
+				Round:         cur,
+				PreviousRound: prev,
+				Message:       fmt.Sprintf("round #%d appears more than once (%s and %s)", cur, sorted[i-1].RoundID, sorted[i].RoundID),
+			})
+		case cur > prev+1:
+			missing := fmt.Sprintf("round #%d is", prev+1)
+			if cur-prev > 2 {
+				missing = fmt.Sprintf("rounds #%d–#%d are", prev+1, cur-1)
+			}
+			report.addFinding(ChainFinding{
+				Kind:          FindingGap,
+				Round:         cur,
+				PreviousRound: prev,
+				Message:       fmt.Sprintf("%s missing between #%d and #%d", missing, prev, cur),
+			})
+		case sorted[i].PreviousHash != sorted[i-1].ServerHash:
+			report.addFinding(ChainFinding{
+				Kind:          FindingBrokenLink,
+				Round:         cur,
+				PreviousRound: prev,
+				Message: fmt.Sprintf("round #%d's previous_hash %s is not round #%d's server_hash %s",
+					cur, ShortHash(sorted[i].PreviousHash), prev, ShortHash(sorted[i-1].ServerHash)),
+			})
+		}
+	}
+
+	firstUse := map[string]RoundVerificationData{}
+	for _, data := range sorted {
+		if data.ServerSeed == "" {
+			continue
+		}
+		if first, seen := firstUse[data.ServerSeed]; seen {
+			report.addFinding(ChainFinding{
+				Kind:          FindingDuplicateSeed,
+				Round:         data.RoundNumber,
+				PreviousRound: first.RoundNumber,
+				Message:       fmt.Sprintf("round #%d reuses the server seed of round #%d", data.RoundNumber, first.RoundNumber),
+			})
+			continue
+		}
+		firstUse[data.ServerSeed] = data
+	}
+	return report
+}
+
+func (r *ChainReport) addFinding(finding ChainFinding) {
+	r.Findings = append(r.Findings, finding)
+	r.Passed = false
+}
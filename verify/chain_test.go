@@ -0,0 +1,121 @@
+package verify
+
+import (
+	"strings"
+	"testing"
+)
+
+// linkedRounds returns n valid rounds numbered from 1, each linking to the
+// server hash of the one before
+func linkedRounds(n int) []RoundVerificationData {
+	var rounds []RoundVerificationData
+	previous := ""
+	for i := 1; i <= n; i++ {
+		data := seededRound(0x100+i, threeBets...)
+		data.RoundNumber, data.PreviousHash = i, previous
+		data.Result = calculateResult(data.ServerSeed, data.ClientSeed, data.RoundNumber, data.PreviousHash)
+		data.WinnerAddress = VerifyRoundWithOptions(data, Options{Game: defaultGame()}).ComputedWinner
+		rounds = append(rounds, data)
+		previous = data.ServerHash
+	}
+	return rounds
+}
+
+func TestVerifyChain(t *testing.T) {
+	tests := []struct {
+		name         string
+		change       func(rounds []RoundVerificationData)
+		opts         Options
+		wantFindings []string
+		wantFailed   int
+	}{
+		{name: "linked chain"},
+		{
+			name:         "gap",
+			change:       func(r []RoundVerificationData) { r[2].RoundNumber = 5 },
+			wantFindings: []string{FindingGap},
+			// the round number is hashed into the result
+			wantFailed: 1,
+		},
+		{
+			name:         "broken link",
+			change:       func(r []RoundVerificationData) { r[1].PreviousHash = r[2].ServerHash },
+			wantFindings: []string{FindingBrokenLink},
+			wantFailed:   1,
+		},
+		{
+			name:       "uppercase hash fails by default",
+			change:     func(r []RoundVerificationData) { r[0].ServerHash = strings.ToUpper(r[0].ServerHash) },
+			wantFailed: 1,
+			// round 2 still links to the lowercase hash it was built with
+			wantFindings: []string{FindingBrokenLink},
+		},
+		{
+			name:         "uppercase hash passes with the options",
+			change:       func(r []RoundVerificationData) { r[0].ServerHash = strings.ToUpper(r[0].ServerHash) },
+			opts:         Options{HexCaseInsensitive: true},
+			wantFindings: []string{FindingBrokenLink},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rounds := linkedRounds(3)
+			if tt.change != nil {
+				tt.change(rounds)
+			}
+			opts := tt.opts
+			opts.Game = defaultGame()
+			report := VerifyChain(rounds, opts)
+
+			var kinds []string
+			for _, finding := range report.Findings {
+				kinds = append(kinds, finding.Kind)
+			}
+			if strings.Join(kinds, ",") != strings.Join(tt.wantFindings, ",") {
+				t.Errorf("findings %v, want %v", kinds, tt.wantFindings)
+			}
+			failed := 0
+			for _, result := range report.Results {
+				if !result.Passed {
+					failed++
+				}
+			}
+			if len(report.Results) != len(rounds) || failed != tt.wantFailed {
+				t.Errorf("%d results with %d failed, want %d with %d failed", len(report.Results), failed, len(rounds), tt.wantFailed)
+			}
+			if report.Passed != (tt.wantFindings == nil && tt.wantFailed == 0) {
+				t.Errorf("passed = %v with findings %v and %d failed rounds", report.Passed, kinds, failed)
+			}
+		})
+	}
+}
+
+func TestCheckChainLinks(t *testing.T) {
+	rounds := linkedRounds(3)
+	var results []*Report
+	for _, data := range rounds {
+		results = append(results, VerifyRoundWithOptions(data, Options{Game: defaultGame()}))
+	}
+
+	tests := []struct {
+		name       string
+		results    []*Report
+		wantPassed bool
+		wantFailed int
+	}{
+		{name: "every round passed", results: results, wantPassed: true},
+		{name: "no results", wantPassed: true},
+		{name: "a round failed", results: []*Report{results[0], {Passed: false}, results[2]}, wantFailed: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := CheckChainLinks(rounds, tt.results)
+			if len(report.Findings) != 0 || report.Results != nil {
+				t.Fatalf("findings %v and %d results, want neither", report.Findings, len(report.Results))
+			}
+			if report.Passed != tt.wantPassed || report.FailedRounds != tt.wantFailed {
+				t.Errorf("passed = %v with %d failed rounds, want %v with %d", report.Passed, report.FailedRounds, tt.wantPassed, tt.wantFailed)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package verify
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// sprintfClientSeed is the client seed as first implemented, with one
+// fmt.Sprintf string per amount and three writes per bet; the buffered
+// implementation must hash exactly the same bytes
+func sprintfClientSeed(bets []VerificationBet) string {
+	h := sha256.New()
+	for _, bet := range SortBets(bets) {
+		h.Write([]byte(bet.PlayerAddress))
+		h.Write([]byte(fmt.Sprintf("%.3f", bet.Amount)))
+		h.Write([]byte(bet.GiftID))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// randomBets returns n bets with distinct addresses and amounts of up to
+// three decimals, shuffled out of address order
+func randomBets(n int, seed int64) []VerificationBet {
+	rng := rand.New(rand.NewSource(seed))
+	bets := make([]VerificationBet, n)
+	for i := range bets {
+		bets[i] = VerificationBet{
+			PlayerAddress: fmt.Sprintf("EQ%062x", rng.Int63()),
+			Amount:        float64(rng.Intn(1000000)) / 1000,
+		}
+		if i%3 == 0 {
+			bets[i].GiftID = fmt.Sprintf("gift-%d", i)
+		}
+	}
+	return bets
+}
+
+func TestComputeClientSeedMatchesSprintf(t *testing.T) {
+	tests := []struct {
+		name string
+		bets []VerificationBet
+		want string
+	}{
+		{name: "published round", bets: threeBets, want: "1782ef25d832791252725061c8b7a5a7e76c80facd4594b9554f3ac617f57754"},
+		{name: "no bets", want: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+		{name: "awkward amounts", bets: []VerificationBet{
+			{PlayerAddress: "EQa", Amount: 0.1},
+			{PlayerAddress: "EQb", Amount: 10.5},
+			{PlayerAddress: "EQc", Amount: 1e21},
+			{PlayerAddress: "EQd", Amount: 0.0005},
+			{PlayerAddress: "EQe", Amount: 2.675},
+			{PlayerAddress: "EQf", Amount: math.SmallestNonzeroFloat64},
+		}},
+		{name: "repeated addresses", bets: []VerificationBet{
+			{PlayerAddress: "EQb", Amount: 2, GiftID: "g2"},
+			{PlayerAddress: "EQa", Amount: 1, GiftID: "g1"},
+			{PlayerAddress: "EQb", Amount: 3, GiftID: "g3"},
+		}},
+		{name: "100k bets", bets: randomBets(100000, 1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, want := ComputeClientSeed(tt.bets), sprintfClientSeed(tt.bets)
+			if got != want {
+				t.Fatalf("ComputeClientSeed = %s, per-bet strings give %s", got, want)
+			}
+			if tt.want != "" && got != tt.want {
+				t.Errorf("ComputeClientSeed = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func BenchmarkComputeClientSeed100k(b *testing.B) {
+	bets := randomBets(100000, 1)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ComputeClientSeed(bets)
+	}
+}
+
+// BenchmarkSprintfClientSeed100k is the per-bet string baseline the
+// buffered implementation replaced, for comparing allocations
+func BenchmarkSprintfClientSeed100k(b *testing.B) {
+	bets := randomBets(100000, 1)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sprintfClientSeed(bets)
+	}
+}
+
+func TestSortBetsKeepsRepeatedAddressOrder(t *testing.T) {
+	// More than 12 bets, where sort.Slice stops using insertion sort and
+	// stops keeping equal addresses in their listed order
+	var listed, want []VerificationBet
+	for i := 0; i < 30; i++ {
+		address := []string{"EQc", "EQa", "EQb"}[i%3]
+		listed = append(listed, VerificationBet{PlayerAddress: address, Amount: float64(i + 1), GiftID: fmt.Sprintf("g%d", i)})
+	}
+	for _, address := range []string{"EQa", "EQb", "EQc"} {
+		for _, bet := range listed {
+			if bet.PlayerAddress == address {
+				want = append(want, bet)
+			}
+		}
+	}
+
+	h := sha256.New()
+	for _, bet := range want {
+		fmt.Fprintf(h, "%s%.3f%s", bet.PlayerAddress, bet.Amount, bet.GiftID)
+	}
+	if got, want := ComputeClientSeed(listed), hex.EncodeToString(h.Sum(nil)); got != want {
+		t.Errorf("ComputeClientSeed = %s, want %s from bets in stable address order", got, want)
+	}
+
+	ranges, err := ComputeWinnerRanges(listed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, bet := range want {
+		if ranges[i].Player != bet.PlayerAddress || ranges[i].Amount != bet.Amount {
+			t.Fatalf("range %d is %s's bet of %g, want %s's bet of %g", i, ranges[i].Player, ranges[i].Amount, bet.PlayerAddress, bet.Amount)
+		}
+	}
+}
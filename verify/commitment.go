@@ -0,0 +1,29 @@
+package verify
+
+import (
+	"encoding/hex"
+	"fmt"
+)
+
+// ValidateCommitment checks that a commitment is hex of the length produced
+// by the game's hash algorithm
+func ValidateCommitment(game GameConfig, commitment string) error {
+	want := game.NewHash().Size() * 2
+	if len(commitment) != want {
+		return fmt.Errorf("commitment is %d hex chars, %s hashes are %d", len(commitment), game.HashAlgorithm, want)
+	}
+	if _, err := hex.DecodeString(commitment); err != nil {
+		return fmt.Errorf("commitment is not valid hex: %v", err)
+	}
+	return nil
+}
+
+// PublishedCommitment is a server hash commitment fetched from the location
+// the operator published it at before the round, used by check #1 in place
+// of the round data's own server_hash
+type PublishedCommitment struct {
+	Source string `json:"source"`
+	// URL is where Source was fetched from, after resolving ipfs://
+	URL  string `json:"url"`
+	Hash string `json:"hash"`
+}
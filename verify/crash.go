@@ -0,0 +1,36 @@
+package verify
+
+import (
+	"fmt"
+	"math"
+)
+
+// crashMultiplierCap is the highest crash multiplier a round can declare;
+// results close enough to 100 to exceed it, and 100 itself, crash at the cap
+const crashMultiplierCap = 1000.0
+
+// crashMultiplier derives the crash game's multiplier from the jackpot
+// result: 99 / (100 - result), rounded down to two decimals and capped at
+// crashMultiplierCap. The 1% the formula keeps below a fair 100 / (100 -
+// result) is the house edge.
+func crashMultiplier(result float64) float64 {
+	if result >= 100 {
+		return crashMultiplierCap
+	}
+	// The epsilon keeps exact multipliers such as 2.00 from flooring to 1.99
+	multiplier := math.Floor(99/(100-result)*100+1e-9) / 100
+	return math.Min(multiplier, crashMultiplierCap)
+}
+
+// crashMultiplierCheck recomputes the declared crash multiplier from the
+// round's claimed result. The result itself is verified by check 3.
+func crashMultiplierCheck(data RoundVerificationData) Check {
+	computed := fmt.Sprintf("%.2f", crashMultiplier(data.Result))
+	claimed := fmt.Sprintf("%.2f", data.CrashMultiplier)
+	return Check{
+		Name:     CheckCrashMultiplier,
+		Passed:   computed == claimed,
+		Expected: computed,
+		Actual:   claimed,
+	}
+}
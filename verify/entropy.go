@@ -0,0 +1,92 @@
+package verify
+
+import (
+	"fmt"
+	"math"
+	"unicode"
+)
+
+// SeedStrength is a rough estimate of how guessable a server seed is
+type SeedStrength struct {
+	Length       int     `json:"length"`
+	AlphabetSize int     `json:"alphabet_size"`
+	Bits         float64 `json:"bits"`
+}
+
+// estimateSeedStrength bounds the entropy of a seed two ways and keeps the
+// lower: the alphabet implied by the character classes it uses, and the
+// Shannon entropy of its actual character distribution. A long seed of
+// repeated characters therefore still scores low.
+func estimateSeedStrength(seed string) SeedStrength {
+	strength := SeedStrength{Length: len([]rune(seed))}
+	if strength.Length == 0 {
+		return strength
+	}
+
+	var lower, upper, digit, other bool
+	counts := make(map[rune]int)
+	for _, r := range seed {
+		counts[r]++
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			other = true
+		}
+	}
+
+	switch {
+	case isHex(seed):
+		strength.AlphabetSize = 16
+	case other:
+		strength.AlphabetSize = 94
+	default:
+		if lower {
+			strength.AlphabetSize += 26
+		}
+		if upper {
+			strength.AlphabetSize += 26
+		}
+		if digit {
+			strength.AlphabetSize += 10
+		}
+	}
+
+	n := float64(strength.Length)
+	shannon := 0.0
+	for _, count := range counts {
+		p := float64(count) / n
+		shannon -= p * math.Log2(p)
+	}
+
+	strength.Bits = math.Min(n*math.Log2(float64(strength.AlphabetSize)), n*shannon)
+	return strength
+}
+
+func isHex(s string) bool {
+	for _, r := range s {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'f' || r >= 'A' && r <= 'F') {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// seedStrengthCheck fails when the estimated seed entropy is below minBits
+func seedStrengthCheck(seed string, minBits float64) Check {
+	strength := estimateSeedStrength(seed)
+	check := Check{
+		Name:     CheckSeedStrength,
+		Passed:   strength.Bits >= minBits,
+		Expected: fmt.Sprintf(">= %.0f bits", minBits),
+		Actual:   fmt.Sprintf("~%.0f bits (%d chars, alphabet of %d)", strength.Bits, strength.Length, strength.AlphabetSize),
+	}
+	if !check.Passed {
+		check.Error = fmt.Sprintf("server seed is only %d chars with ~%.0f bits of entropy", strength.Length, strength.Bits)
+	}
+	return check
+}
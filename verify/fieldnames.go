@@ -0,0 +1,115 @@
+package verify
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Older API versions emit camelCase keys (serverSeed, playerAddress, giftId)
+// where the current API uses snake_case. Both are accepted: any camelCase key
+// whose snake_case form is absent is renamed before decoding, so new fields
+// pick up the alternate spelling without maintaining an alias table.
+
+// UnmarshalJSON accepts both snake_case and camelCase field names, and a
+// total pot given as a decimal string. A sharded round's bets are flattened
+// into Bets when no flat list is given.
+func (d *RoundVerificationData) UnmarshalJSON(raw []byte) error {
+	type plain RoundVerificationData
+	normalized, err := snakeCaseKeys(raw)
+	if err != nil {
+		return err
+	}
+	aux := struct {
+		*plain
+		TotalPot json.RawMessage `json:"total_pot"`
+	}{plain: (*plain)(d)}
+	if err := json.Unmarshal(normalized, &aux); err != nil {
+		return err
+	}
+	if len(d.Bets) == 0 {
+		d.Bets = flattenShards(d.BetShards)
+	}
+	return decodeAmount("total_pot", aux.TotalPot, &d.TotalPot)
+}
+
+// UnmarshalJSON accepts both snake_case and camelCase field names, and an
+// amount given as a decimal string
+func (b *VerificationBet) UnmarshalJSON(raw []byte) error {
+	type plain VerificationBet
+	normalized, err := snakeCaseKeys(raw)
+	if err != nil {
+		return err
+	}
+	aux := struct {
+		*plain
+		Amount json.RawMessage `json:"amount"`
+	}{plain: (*plain)(b)}
+	if err := json.Unmarshal(normalized, &aux); err != nil {
+		return err
+	}
+	return decodeAmount("amount", aux.Amount, &b.Amount)
+}
+
+// decodeAmount decodes a TON amount sent either as a JSON number or, by
+// backends avoiding float rounding on their side, as a string such as
+// "10.500". The string must itself be a plain JSON number: no hex, NaN,
+// Inf or surrounding whitespace.
+func decodeAmount(field string, raw json.RawMessage, amount *float64) error {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err != nil {
+		return json.Unmarshal(raw, amount)
+	}
+	// json.Unmarshal alone would skip the surrounding whitespace
+	if strings.TrimSpace(text) != text || json.Unmarshal([]byte(text), amount) != nil {
+		return fmt.Errorf("%s %q is not a decimal number", field, text)
+	}
+	return nil
+}
+
+// snakeCaseKeys rewrites the top-level camelCase keys of a JSON object to
+// snake_case. Snake_case keys win when both spellings are present.
+func snakeCaseKeys(raw []byte) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+
+	renamed := false
+	for key, value := range fields {
+		snake := toSnakeCase(key)
+		if snake == key {
+			continue
+		}
+		if _, exists := fields[snake]; !exists {
+			fields[snake] = value
+		}
+		delete(fields, key)
+		renamed = true
+	}
+	if !renamed {
+		return raw, nil
+	}
+	return json.Marshal(fields)
+}
+
+// toSnakeCase converts camelCase to snake_case, keeping acronyms together
+// ("roundID" becomes "round_id", "giftId" becomes "gift_id")
+func toSnakeCase(s string) string {
+	var b strings.Builder
+	runes := []rune(s)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,40 @@
+package verify
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestBetAmountDecoding(t *testing.T) {
+	tests := []struct {
+		name      string
+		amount    string
+		want      float64
+		wantError string
+	}{
+		{name: "number", amount: `10.5`, want: 10.5},
+		{name: "string", amount: `"10.500"`, want: 10.5},
+		{name: "exponent string", amount: `"1e1"`, want: 10},
+		{name: "leading space", amount: `" 10.5"`, wantError: `amount " 10.5" is not a decimal number`},
+		{name: "trailing newline", amount: `"10.5\n"`, wantError: "is not a decimal number"},
+		{name: "hex", amount: `"0x10"`, wantError: "is not a decimal number"},
+		{name: "NaN", amount: `"NaN"`, wantError: "is not a decimal number"},
+		{name: "empty string", amount: `""`, wantError: "is not a decimal number"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var bet VerificationBet
+			err := json.Unmarshal([]byte(`{"player_address": "EQa", "amount": `+tt.amount+`}`), &bet)
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Fatalf("error %v, want one containing %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil || bet.Amount != tt.want {
+				t.Errorf("amount %v, error %v; want %v", bet.Amount, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+package verify
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GameConfig describes how a jackpot variant derives its result
+type GameConfig struct {
+	Name          string  `json:"name"`
+	Description   string  `json:"description,omitempty"`
+	HashAlgorithm string  `json:"hash_algorithm"`
+	Modulus       int64   `json:"modulus"`
+	Divisor       float64 `json:"divisor"`
+	MessageFormat string  `json:"message_format"`
+	// TieBreak resolves results landing exactly on a boundary between two
+	// players with a secondary HMAC draw instead of the half-open range rule
+	TieBreak bool `json:"tie_break,omitempty"`
+	// ClientSeedMode is "sha256" (the default) to hash the serialized bets,
+	// or "hmac" to HMAC-SHA256 them keyed by ClientSeedKey, typically a
+	// public game id
+	ClientSeedMode string `json:"client_seed_mode,omitempty"`
+	ClientSeedKey  string `json:"client_seed_key,omitempty"`
+	// BetOrder is "sorted" (the default) to hash bets sorted by player
+	// address, or "insertion" to hash them in the order they were placed,
+	// as listed in the round data
+	BetOrder string `json:"bet_order,omitempty"`
+	// RangeOrder is the order winner ranges are assigned in: "sorted" (the
+	// default) by player address, or "insertion" as listed. It is
+	// independent of BetOrder, for backends that hash one way and assign
+	// ranges the other.
+	RangeOrder string `json:"range_order,omitempty"`
+	// MinWinningBet excludes smaller bets from the winner ranges: they still
+	// count toward the pot and the client seed, but cannot win. Zero lets
+	// every bet win.
+	MinWinningBet float64 `json:"min_winning_bet,omitempty"`
+	// UniqueGifts marks gifts as unique items that can be bet only once:
+	// a gift id appearing in two bets fails the round
+	UniqueGifts bool `json:"unique_gifts,omitempty"`
+	// WinnerRule is "range" (the default) to award the player whose range
+	// contains the result, or "nearest" to award the player whose range
+	// midpoint is closest to it
+	WinnerRule string `json:"winner_rule,omitempty"`
+	// ResultRounding is how results are brought to three decimals before
+	// comparison: "round" (the default), "truncate" or "ceil", matching
+	// how the backend formats them
+	ResultRounding string `json:"result_rounding,omitempty"`
+	// HouseAddresses are the operator's own addresses. Their bets are
+	// hashed and ranged like any other; they are only labeled as the house.
+	HouseAddresses []string `json:"house_addresses,omitempty"`
+}
+
+// Client seed modes
+const (
+	ClientSeedSHA256 = "sha256"
+	ClientSeedHMAC   = "hmac"
+)
+
+// Bet orders for the client seed
+const (
+	BetOrderSorted    = "sorted"
+	BetOrderInsertion = "insertion"
+)
+
+// Winner rules
+const (
+	WinnerRuleRange   = "range"
+	WinnerRuleNearest = "nearest"
+)
+
+// Result rounding modes
+const (
+	RoundingRound    = "round"
+	RoundingTruncate = "truncate"
+	RoundingCeil     = "ceil"
+)
+
+const DefaultGameName = "jackpot"
+
+// defaultMessageFormat is the HMAC message used by the LazyBox server
+const defaultMessageFormat = "{server_seed}:{client_seed}:{round_number}:{previous_hash}"
+
+var hashAlgorithms = map[string]func() hash.Hash{
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// GameRegistry holds the built-in profiles plus any loaded from --games-file
+var GameRegistry = map[string]GameConfig{
+	"jackpot": {
+		Name:          "jackpot",
+		Description:   "LazyBox jackpot (HMAC-SHA256, result in [0, 100])",
+		HashAlgorithm: "sha256",
+		Modulus:       100001,
+		Divisor:       1000.0,
+		MessageFormat: defaultMessageFormat,
+	},
+	"jackpot-sha512": {
+		Name:          "jackpot-sha512",
+		Description:   "Jackpot variant committing and drawing with SHA-512",
+		HashAlgorithm: "sha512",
+		Modulus:       100001,
+		Divisor:       1000.0,
+		MessageFormat: defaultMessageFormat,
+	},
+}
+
+func defaultGame() GameConfig {
+	return GameRegistry[DefaultGameName]
+}
+
+// LookupGame returns the named profile or an error listing the known ones
+func LookupGame(name string) (GameConfig, error) {
+	game, ok := GameRegistry[name]
+	if !ok {
+		return GameConfig{}, fmt.Errorf("unknown game %q (available: %s)", name, strings.Join(GameNames(), ", "))
+	}
+	return game, nil
+}
+
+// GameNames returns the names of the registered profiles, sorted
+func GameNames() []string {
+	names := make([]string, 0, len(GameRegistry))
+	for name := range GameRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RegisterGames registers the profiles in a JSON array of game
+// configurations, overriding built-ins of the same name
+func RegisterGames(data []byte) error {
+	var games []GameConfig
+	if err := json.Unmarshal(data, &games); err != nil {
+		return fmt.Errorf("failed to parse games file: %v", err)
+	}
+
+	for _, game := range games {
+		if game.MessageFormat == "" {
+			game.MessageFormat = defaultMessageFormat
+		}
+		if game.HashAlgorithm == "" {
+			game.HashAlgorithm = "sha256"
+		}
+		if err := game.Validate(); err != nil {
+			return err
+		}
+		GameRegistry[game.Name] = game
+	}
+	return nil
+}
+
+// Validate reports the first setting of the profile the verifier cannot apply
+func (g GameConfig) Validate() error {
+	if g.Name == "" {
+		return fmt.Errorf("game profile is missing a name")
+	}
+	if _, ok := hashAlgorithms[g.HashAlgorithm]; !ok {
+		return fmt.Errorf("game %q: unsupported hash algorithm %q", g.Name, g.HashAlgorithm)
+	}
+	if g.Modulus <= 0 {
+		return fmt.Errorf("game %q: modulus must be positive", g.Name)
+	}
+	if g.Divisor <= 0 {
+		return fmt.Errorf("game %q: divisor must be positive", g.Name)
+	}
+	switch g.ClientSeedMode {
+	case "", ClientSeedSHA256, ClientSeedHMAC:
+	default:
+		return fmt.Errorf("game %q: unsupported client seed mode %q (want %s or %s)",
+			g.Name, g.ClientSeedMode, ClientSeedSHA256, ClientSeedHMAC)
+	}
+	switch g.BetOrder {
+	case "", BetOrderSorted, BetOrderInsertion:
+	default:
+		return fmt.Errorf("game %q: unsupported bet order %q (want %s or %s)",
+			g.Name, g.BetOrder, BetOrderSorted, BetOrderInsertion)
+	}
+	switch g.RangeOrder {
+	case "", BetOrderSorted, BetOrderInsertion:
+	default:
+		return fmt.Errorf("game %q: unsupported range order %q (want %s or %s)",
+			g.Name, g.RangeOrder, BetOrderSorted, BetOrderInsertion)
+	}
+	if g.MinWinningBet < 0 || !finite(g.MinWinningBet) {
+		return fmt.Errorf("game %q: minimum winning bet %g must be a non-negative number", g.Name, g.MinWinningBet)
+	}
+	switch g.WinnerRule {
+	case "", WinnerRuleRange:
+	case WinnerRuleNearest:
+		if g.TieBreak {
+			return fmt.Errorf("game %q: tie-breaks apply only to the %s winner rule", g.Name, WinnerRuleRange)
+		}
+	default:
+		return fmt.Errorf("game %q: unsupported winner rule %q (want %s or %s)",
+			g.Name, g.WinnerRule, WinnerRuleRange, WinnerRuleNearest)
+	}
+	switch g.ResultRounding {
+	case "", RoundingRound, RoundingTruncate, RoundingCeil:
+	default:
+		return fmt.Errorf("game %q: unsupported result rounding %q (want %s, %s or %s)",
+			g.Name, g.ResultRounding, RoundingRound, RoundingTruncate, RoundingCeil)
+	}
+	return nil
+}
+
+// NewHash returns a new hasher for the game's hash algorithm
+func (g GameConfig) NewHash() hash.Hash {
+	return hashAlgorithms[g.HashAlgorithm]()
+}
+
+// clientSeedHash returns the hasher the serialized bets are written into,
+// and the trace label of its output
+func (g GameConfig) clientSeedHash() (hash.Hash, string) {
+	if g.ClientSeedMode == ClientSeedHMAC {
+		return hmac.New(sha256.New, []byte(g.ClientSeedKey)), "client_seed.hmac_sha256"
+	}
+	return sha256.New(), "client_seed.sha256"
+}
+
+// commitHash hashes the server seed the way the game publishes its commitment
+func (g GameConfig) commitHash(serverSeed string) string {
+	h := g.NewHash()
+	h.Write([]byte(serverSeed))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// FormatResult brings a result to three decimals with the game's rounding
+// mode. The tolerance absorbs float error in values that are already exact
+// at three decimals, such as 42.156 stored as 42.15599999.
+func (g GameConfig) FormatResult(result float64) string {
+	const tolerance = 1e-9
+	switch g.ResultRounding {
+	case RoundingTruncate:
+		result = math.Floor(result*1000+tolerance) / 1000
+	case RoundingCeil:
+		result = math.Ceil(result*1000-tolerance) / 1000
+	}
+	return strconv.FormatFloat(result, 'f', 3, 64)
+}
+
+// Message builds the HMAC input from the game's message format
+func (g GameConfig) Message(serverSeed, clientSeed string, roundNumber int, previousHash string) string {
+	return strings.NewReplacer(
+		"{server_seed}", serverSeed,
+		"{client_seed}", clientSeed,
+		"{round_number}", strconv.Itoa(roundNumber),
+		"{previous_hash}", previousHash,
+	).Replace(g.MessageFormat)
+}
@@ -0,0 +1,47 @@
+package verify
+
+import (
+	"fmt"
+	"strings"
+)
+
+// uniqueGiftsCheck fails rounds where a gift id appears in more than one bet.
+// In games whose gifts are unique NFTs a gift can only be bet once, so a
+// repeated id means the same gift was counted twice. Bets without a gift id
+// are not gift bets and are exempt.
+func uniqueGiftsCheck(data RoundVerificationData) Check {
+	check := Check{
+		Name:     CheckUniqueGifts,
+		Passed:   true,
+		Expected: "every gift id in at most one bet",
+	}
+
+	players := make(map[string][]string)
+	var order []string
+	gifts := 0
+	for _, bet := range data.Bets {
+		if bet.GiftID == "" {
+			continue
+		}
+		gifts++
+		if _, ok := players[bet.GiftID]; !ok {
+			order = append(order, bet.GiftID)
+		}
+		players[bet.GiftID] = append(players[bet.GiftID], bet.PlayerAddress)
+	}
+	check.Actual = fmt.Sprintf("%d distinct gift ids in %d gift bets", len(order), gifts)
+
+	var problems []string
+	for _, gift := range order {
+		if bettors := players[gift]; len(bettors) > 1 {
+			problems = append(problems, fmt.Sprintf("gift %s is in %d bets, by %s",
+				gift, len(bettors), strings.Join(bettors, ", ")))
+		}
+	}
+	if len(problems) > 0 {
+		check.Passed = false
+		check.Actual = fmt.Sprintf("%d duplicated gift id(s)", len(problems))
+		check.Error = strings.Join(problems, "; ")
+	}
+	return check
+}
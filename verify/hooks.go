@@ -0,0 +1,35 @@
+package verify
+
+// VerifyHooks lets library callers observe a verification as it runs.
+// Every field is optional.
+type VerifyHooks struct {
+	// OnCheckStart is called before each check is performed
+	OnCheckStart func(name string)
+	// OnCheckComplete is called after each check, including skipped ones,
+	// with the values that were compared
+	OnCheckComplete func(name string, passed bool, expected, actual interface{})
+	// Abort is consulted after each check; returning true stops the
+	// verification and records the remaining checks as skipped
+	Abort func(check Check) bool
+}
+
+// VerifyRoundWithHooks verifies a round against the default game profile,
+// calling hooks as each check completes. An aborted verification is marked
+// Aborted and never counts as passed.
+func VerifyRoundWithHooks(data RoundVerificationData, hooks VerifyHooks) *Report {
+	return VerifyRoundWithOptions(data, Options{Game: defaultGame(), Hooks: hooks})
+}
+
+func (h VerifyHooks) checkStart(name string) {
+	if h.OnCheckStart != nil {
+		h.OnCheckStart(name)
+	}
+}
+
+// checkComplete reports a finished check and returns whether to abort
+func (h VerifyHooks) checkComplete(check Check) bool {
+	if h.OnCheckComplete != nil {
+		h.OnCheckComplete(check.Name, check.Passed, check.Expected, check.Actual)
+	}
+	return h.Abort != nil && h.Abort(check)
+}
@@ -0,0 +1,63 @@
+package verify
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVerifyRoundWithHooks(t *testing.T) {
+	data := seededRound(0x42, threeBets...)
+	data.WinnerAddress = VerifyRoundWithOptions(data, Options{Game: defaultGame()}).ComputedWinner
+	all := []string{CheckBetAmounts, CheckServerHash, CheckClientSeed, CheckResult, CheckWinner}
+
+	tests := []struct {
+		name    string
+		abortAt string // "" never aborts
+		// wantRun are the checks the hooks see, in order
+		wantRun []string
+	}{
+		{name: "every check", wantRun: all},
+		{name: "abort after the server hash", abortAt: CheckServerHash, wantRun: all[:2]},
+		{name: "abort after the last check", abortAt: CheckWinner, wantRun: all},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var started, completed []string
+			hooks := VerifyHooks{
+				OnCheckStart: func(name string) { started = append(started, name) },
+				OnCheckComplete: func(name string, passed bool, expected, actual interface{}) {
+					completed = append(completed, name)
+					if !passed {
+						t.Errorf("%s failed: expected %v, got %v", name, expected, actual)
+					}
+				},
+				Abort: func(check Check) bool { return check.Name == tt.abortAt },
+			}
+			result := VerifyRoundWithHooks(data, hooks)
+
+			want := strings.Join(tt.wantRun, ",")
+			if got := strings.Join(started, ","); got != want {
+				t.Errorf("started %s, want %s", got, want)
+			}
+			if got := strings.Join(completed, ","); got != want {
+				t.Errorf("completed %s, want %s", got, want)
+			}
+
+			var names []string
+			for _, check := range result.Checks {
+				names = append(names, check.Name)
+			}
+			if got := strings.Join(names, ","); got != strings.Join(all, ",") {
+				t.Fatalf("report checks %s, want every check %s", got, strings.Join(all, ","))
+			}
+			wantSkipped := strings.Join(all[len(tt.wantRun):], ",")
+			if got := strings.Join(result.SkippedChecks(), ","); got != wantSkipped {
+				t.Errorf("skipped %q, want %q", got, wantSkipped)
+			}
+			aborted := tt.abortAt != ""
+			if result.Aborted != aborted || result.Passed == aborted || result.Partial {
+				t.Errorf("aborted = %v, passed = %v, partial = %v; want aborted %v", result.Aborted, result.Passed, result.Partial, aborted)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package verify
+
+import (
+	"encoding/hex"
+	"fmt"
+)
+
+// seedMerkleRoot recomputes the root of a Merkle tree committing to a batch
+// of server seeds, from the commitment hash of the seed at index (its leaf)
+// and the sibling hashes on the path up to the root, leaf level first. A
+// parent is the game's hash of its left and right children's raw bytes; bit
+// k of index tells whether the node at level k is a right child.
+func seedMerkleRoot(game GameConfig, leaf string, index int, proof []string) (string, error) {
+	if index < 0 {
+		return "", fmt.Errorf("seed index %d is negative", index)
+	}
+	if len(proof) < 63 && index>>uint(len(proof)) != 0 {
+		return "", fmt.Errorf("seed index %d does not fit a tree of depth %d", index, len(proof))
+	}
+	node, err := hex.DecodeString(leaf)
+	if err != nil {
+		return "", fmt.Errorf("leaf is not valid hex: %v", err)
+	}
+	for level, siblingHex := range proof {
+		sibling, err := hex.DecodeString(siblingHex)
+		if err != nil {
+			return "", fmt.Errorf("proof hash %d is not valid hex: %v", level, err)
+		}
+		if len(sibling) != game.NewHash().Size() {
+			return "", fmt.Errorf("proof hash %d is %d bytes, not a %s digest", level, len(sibling), game.HashAlgorithm)
+		}
+		h := game.NewHash()
+		if index>>uint(level)&1 == 0 {
+			h.Write(node)
+			h.Write(sibling)
+		} else {
+			h.Write(sibling)
+			h.Write(node)
+		}
+		node = h.Sum(nil)
+	}
+	return hex.EncodeToString(node), nil
+}
@@ -0,0 +1,497 @@
+package verify
+
+import (
+	"fmt"
+	"math"
+)
+
+// WinnerRange is the slice of [0, 100) a player wins with
+type WinnerRange struct {
+	Player  string  `json:"player"`
+	Start   float64 `json:"start"`
+	End     float64 `json:"end"`
+	Percent float64 `json:"percent"`
+	Amount  float64 `json:"amount"`
+}
+
+// Contains reports whether result falls in the half-open range [Start, End).
+// The first range starts at exactly 0 (prefix[0] is 0), so a result of 0.000
+// is won by the first sorted player. Every bet amount is positive, so only a
+// share too small to register leaves the empty range [x, x), which never wins.
+func (r WinnerRange) Contains(result float64) bool {
+	return result >= r.Start && result < r.End
+}
+
+// RangeHolds reports whether the range at index i of ranges holds result.
+// Ranges are half-open, except that the last non-empty range also holds
+// its end when that end is the top of the domain: calculateResult can
+// yield exactly 100.000 (modulus 100001), and that draw belongs to the
+// last player rather than to nobody.
+func RangeHolds(ranges []WinnerRange, i int, result float64) bool {
+	r := ranges[i]
+	if r.Contains(result) {
+		return true
+	}
+	if result != ResultDomainMax || r.End != ResultDomainMax || r.Start == r.End {
+		return false
+	}
+	for _, later := range ranges[i+1:] {
+		if later.Start < later.End {
+			return false
+		}
+	}
+	return true
+}
+
+// RangeAssertion states whether the result lies in the claimed winner's
+// half-open range [Start, End)
+type RangeAssertion struct {
+	Player   string  `json:"player"`
+	Start    float64 `json:"start"`
+	End      float64 `json:"end"`
+	Result   float64 `json:"result"`
+	Contains bool    `json:"contains"`
+	// UpperBoundary is set when the result equals the range's End. The
+	// claimed winner then only wins if ranges are read as (start, end],
+	// the opposite of the convention used here.
+	UpperBoundary bool `json:"upper_boundary,omitempty"`
+}
+
+// assertClaimedRange locates the claimed winner's range, preferring the one
+// containing result and then one ending exactly at it, since a player with
+// several bets holds several ranges. It returns nil when the claimed winner
+// has no range at all.
+func assertClaimedRange(ranges []WinnerRange, claimed string, result float64) *RangeAssertion {
+	var found *RangeAssertion
+	for i, r := range ranges {
+		if r.Player != claimed {
+			continue
+		}
+		contains := RangeHolds(ranges, i, result)
+		assertion := &RangeAssertion{
+			Player:        r.Player,
+			Start:         r.Start,
+			End:           r.End,
+			Result:        result,
+			Contains:      contains,
+			UpperBoundary: !contains && result == r.End && r.Start < r.End,
+		}
+		if assertion.Contains {
+			return assertion
+		}
+		if found == nil || (assertion.UpperBoundary && !found.UpperBoundary) {
+			found = assertion
+		}
+	}
+	return found
+}
+
+// coverageTolerance is how far the ranges' total may fall from 100%. Results
+// have three decimals, so a larger gap or overhang could hold a result that
+// no range, or two ranges, would claim.
+const coverageTolerance = 0.001
+
+// RangeCoverage describes how completely a round's ranges tile the result
+// domain
+type RangeCoverage struct {
+	// Total is the percentage of [0, 100) covered, undisclosed share included
+	Total float64 `json:"total"`
+	// Undisclosed is the share left to bets outside the visible list
+	Undisclosed float64 `json:"undisclosed,omitempty"`
+	// Contiguous means the first range starts at 0 and every other range
+	// starts exactly where the previous one ended, with no gap or overlap
+	Contiguous bool `json:"contiguous"`
+	// Complete means the ranges are contiguous and Total is within
+	// coverageTolerance of 100
+	Complete bool `json:"complete"`
+}
+
+// ComputeRangeCoverage measures the ranges' coverage of the domain. When hidden,
+// the domain past the last visible range belongs to undisclosed bets.
+func ComputeRangeCoverage(ranges []WinnerRange, hidden bool) RangeCoverage {
+	var coverage RangeCoverage
+	if len(ranges) == 0 {
+		return coverage
+	}
+	coverage.Contiguous = ranges[0].Start == ResultDomainMin
+	for i := 1; i < len(ranges); i++ {
+		if ranges[i].Start != ranges[i-1].End {
+			coverage.Contiguous = false
+		}
+	}
+	coverage.Total = ranges[len(ranges)-1].End
+	if hidden {
+		coverage.Undisclosed = ResultDomainMax - coverage.Total
+		coverage.Total += coverage.Undisclosed
+	}
+	coverage.Complete = coverage.Contiguous && math.Abs(coverage.Total-ResultDomainMax) <= coverageTolerance
+	return coverage
+}
+
+// ComputeWinnerRanges assigns each bet, sorted by player address, a range
+// proportional to its amount. Boundaries come from one array of cumulative
+// sums so each range starts exactly where the previous one ended; adding
+// percentages one at a time would let float drift open gaps between ranges.
+func ComputeWinnerRanges(bets []VerificationBet) ([]WinnerRange, error) {
+	return computeWinnerRanges(bets, 0, BetOrderSorted)
+}
+
+// WinProbabilities returns each player's chance of winning, before the result
+// is known: the share of the pot their ranges cover. Players with several
+// bets are summed into one entry, and the shares add up to 1. No game
+// profile applies, so a bet below a minimum winning bet still counts. It
+// returns nil when no bet has a positive amount or the amounts cannot be
+// ranged.
+func WinProbabilities(bets []VerificationBet) map[string]float64 {
+	ranges, err := ComputeWinnerRanges(bets)
+	if err != nil || len(ranges) == 0 || ranges[len(ranges)-1].End <= 0 {
+		return nil
+	}
+	total := 0.0
+	for _, r := range ranges {
+		total += r.Amount
+	}
+	probabilities := make(map[string]float64)
+	for _, r := range ranges {
+		probabilities[r.Player] += r.Amount / total
+	}
+	return probabilities
+}
+
+// rangeMathError reports a bet whose amount makes the range arithmetic
+// produce Inf or NaN, such as a corrupt amount near math.MaxFloat64
+type rangeMathError struct {
+	bet    VerificationBet
+	detail string
+}
+
+func (e *rangeMathError) Error() string {
+	return fmt.Sprintf("bet by %s (amount %g) %s", e.bet.PlayerAddress, e.bet.Amount, e.detail)
+}
+
+// rangeTotalError reports bets whose amounts add up to zero or less, which
+// leaves nothing to divide the domain by: every range would be NaN
+type rangeTotalError struct {
+	total float64
+	bets  int
+}
+
+func (e *rangeTotalError) Error() string {
+	return fmt.Sprintf("the %d bets total %g, so no bet has a share of the pot to win with", e.bets, e.total)
+}
+
+// betAmountError reports a bet whose amount is zero or negative. Such a bet
+// has no share of the pot, and a negative one would shrink its neighbours'
+// ranges, so the round is rejected rather than ranged around it.
+type betAmountError struct {
+	bet VerificationBet
+}
+
+func (e *betAmountError) Error() string {
+	return fmt.Sprintf("bet by %s has amount %g; every bet must be positive", e.bet.PlayerAddress, e.bet.Amount)
+}
+
+// checkBetAmounts rejects a bet list with a non-finite or non-positive
+// amount, or whose amounts add up to zero or less
+func checkBetAmounts(bets []VerificationBet) error {
+	total := 0.0
+	for _, bet := range bets {
+		if !finite(bet.Amount) {
+			return &rangeMathError{bet: bet, detail: "is not a finite number"}
+		}
+		if bet.Amount <= 0 {
+			return &betAmountError{bet: bet}
+		}
+		total += bet.Amount
+	}
+	if len(bets) > 0 && total <= 0 {
+		return &rangeTotalError{total: total, bets: len(bets)}
+	}
+	return nil
+}
+
+// finite reports whether v is neither Inf nor NaN
+func finite(v float64) bool {
+	return !math.IsInf(v, 0) && !math.IsNaN(v)
+}
+
+// computeWinnerRanges builds ranges as percentages of denominator, or of the
+// sum of the bets when denominator is not positive, assigning them in order
+// (sorted by address unless it is BetOrderInsertion). Any bet that makes the
+// arithmetic overflow is reported instead of yielding garbage ranges.
+func computeWinnerRanges(bets []VerificationBet, denominator float64, order string) ([]WinnerRange, error) {
+	if err := checkBetAmounts(bets); err != nil {
+		return nil, err
+	}
+	// Ranges follow the same order as the client seed, so a player's
+	// several bets keep their relative order in both
+	sortedBets := bets
+	if order != BetOrderInsertion {
+		sortedBets = SortBets(bets)
+	}
+
+	// Cumulative bet amounts: prefix[i] is the total of the first i bets
+	prefix := make([]float64, len(sortedBets)+1)
+	for i, bet := range sortedBets {
+		prefix[i+1] = prefix[i] + bet.Amount
+		if !finite(prefix[i+1]) {
+			return nil, &rangeMathError{bet: bet, detail: fmt.Sprintf("overflows the running total after %g", prefix[i])}
+		}
+	}
+	totalBets := prefix[len(sortedBets)]
+	if denominator > 0 {
+		totalBets = denominator
+	}
+	if len(sortedBets) > 0 && totalBets <= 0 {
+		return nil, &rangeTotalError{total: totalBets, bets: len(sortedBets)}
+	}
+
+	ranges := make([]WinnerRange, len(sortedBets))
+	for i, bet := range sortedBets {
+		ranges[i] = WinnerRange{
+			Player:  bet.PlayerAddress,
+			Start:   prefix[i] / totalBets * 100.0,
+			End:     prefix[i+1] / totalBets * 100.0,
+			Percent: bet.Amount / totalBets * 100.0,
+			Amount:  bet.Amount,
+		}
+		r := ranges[i]
+		if !finite(r.Start) || !finite(r.End) || !finite(r.Percent) {
+			return nil, &rangeMathError{bet: bet, detail: fmt.Sprintf("yields the non-finite range [%g, %g) of total %g", r.Start, r.End, totalBets)}
+		}
+	}
+	return ranges, nil
+}
+
+// WinnerRanges returns the round's ranges, using its declared
+// RangeDenominator when present. The denominator must cover every visible
+// bet; otherwise some visible range would extend past 100%.
+func (d RoundVerificationData) WinnerRanges() ([]WinnerRange, error) {
+	// Bets below a minimum winning bet hold no range but must still be valid
+	if err := checkBetAmounts(d.Bets); err != nil {
+		return nil, err
+	}
+	if d.AmountsAreShares {
+		return d.shareRanges()
+	}
+	if d.MinWinningBet > 0 {
+		if d.RangeDenominator != 0 {
+			return nil, fmt.Errorf("a range denominator cannot be combined with a minimum winning bet")
+		}
+		return computeWinnerRanges(d.WinningBets(), 0, d.RangeOrder)
+	}
+	if d.RangeDenominator == 0 {
+		return computeWinnerRanges(d.Bets, 0, d.RangeOrder)
+	}
+	if d.RangeDenominator < 0 {
+		return nil, fmt.Errorf("range denominator %.3f is negative", d.RangeDenominator)
+	}
+
+	visible := 0.0
+	for _, bet := range d.Bets {
+		visible += bet.Amount
+	}
+	if d.RangeDenominator < visible-PotTolerance {
+		return nil, fmt.Errorf("range denominator %.3f is less than the visible bets total %.3f",
+			d.RangeDenominator, visible)
+	}
+
+	ranges, err := computeWinnerRanges(d.Bets, d.RangeDenominator, d.RangeOrder)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range ranges {
+		if r.End > ResultDomainMax {
+			return nil, fmt.Errorf("bet by %s (%.3f TON) would need a range up to %.3f, beyond the denominator",
+				r.Player, r.Amount, r.End)
+		}
+	}
+	return ranges, nil
+}
+
+// shareRanges uses bet amounts directly as percentages of the domain. They
+// must add up to 100, give or take the rounding of each share.
+func (d RoundVerificationData) shareRanges() ([]WinnerRange, error) {
+	if d.RangeDenominator != 0 {
+		return nil, fmt.Errorf("a range denominator cannot be combined with amounts given as shares")
+	}
+	if d.MinWinningBet > 0 {
+		return nil, fmt.Errorf("a minimum winning bet cannot be combined with amounts given as shares")
+	}
+	total := 0.0
+	for _, bet := range d.Bets {
+		total += bet.Amount
+	}
+	tolerance := math.Max(shareTolerance, PotTolerance*float64(len(d.Bets)))
+	if math.Abs(total-100) > tolerance {
+		return nil, fmt.Errorf("bet shares add up to %.3f%%, not 100%%", total)
+	}
+	return computeWinnerRanges(d.Bets, 100, d.RangeOrder)
+}
+
+// WinningBets returns the bets eligible to win: all of them unless the game
+// sets a minimum winning bet, in which case smaller bets are left out
+func (d RoundVerificationData) WinningBets() []VerificationBet {
+	if d.MinWinningBet <= 0 {
+		return d.Bets
+	}
+	var eligible []VerificationBet
+	for _, bet := range d.Bets {
+		if bet.Amount >= d.MinWinningBet {
+			eligible = append(eligible, bet)
+		}
+	}
+	return eligible
+}
+
+// canWin reports whether address placed a bet eligible to win
+func (d RoundVerificationData) canWin(address string) bool {
+	for _, bet := range d.WinningBets() {
+		if bet.PlayerAddress == address {
+			return true
+		}
+	}
+	return false
+}
+
+// shareTolerance is the minimum slack allowed when bet shares are summed
+const shareTolerance = 0.01
+
+// HasHiddenShare reports whether the round's ranges leave part of the
+// domain to bets that are not in the visible list
+func (d RoundVerificationData) HasHiddenShare() bool {
+	return d.RangeDenominator > 0
+}
+
+// fallbackRule returns the fallback rule in force: the override, if any,
+// else the round's declared rule
+func (d RoundVerificationData) fallbackRule() string {
+	if d.FallbackOverride != "" {
+		return d.FallbackOverride
+	}
+	return d.FallbackRule
+}
+
+// Fallback rules for a result that falls in no visible range
+const (
+	// FallbackFail treats such a result as a verification failure (default)
+	FallbackFail = "fail"
+	// FallbackLast awards it to the last player in sorted order
+	FallbackLast = "last"
+	// FallbackFirst awards it to the first player in sorted order
+	FallbackFirst = "first"
+)
+
+// NoRangeError reports a result no range contains under the fail rule
+type NoRangeError struct {
+	result float64
+}
+
+func (e *NoRangeError) Error() string {
+	return fmt.Sprintf("result %.3f falls in no winner range and the round declares no fallback rule", e.result)
+}
+
+// winnerForResult returns the player whose range contains result. When part
+// of the domain belongs to undisclosed bets and result lands there, no
+// visible player can be named and "" is returned. Any other result outside
+// every range is resolved by the fallback rule, which must be declared: a
+// silent fallback would hide broken ranges.
+func winnerForResult(ranges []WinnerRange, result float64, hidden bool, fallback string) (string, error) {
+	if len(ranges) == 0 {
+		return "", nil
+	}
+	for i, r := range ranges {
+		if RangeHolds(ranges, i, result) {
+			return r.Player, nil
+		}
+	}
+	if hidden && result >= ranges[len(ranges)-1].End {
+		return "", nil
+	}
+
+	switch fallback {
+	case FallbackLast:
+		return ranges[len(ranges)-1].Player, nil
+	case FallbackFirst:
+		return ranges[0].Player, nil
+	case "", FallbackFail:
+		return "", &NoRangeError{result: result}
+	default:
+		return "", fmt.Errorf("unknown fallback rule %q (want %s, %s or %s)", fallback, FallbackLast, FallbackFirst, FallbackFail)
+	}
+}
+
+// IsHouse reports whether address is one of the house addresses
+func IsHouse(house []string, address string) bool {
+	for _, h := range house {
+		if address != "" && h == address {
+			return true
+		}
+	}
+	return false
+}
+
+// Midpoint is the centre of the range, used by the nearest winner rule
+func (r WinnerRange) Midpoint() float64 {
+	return (r.Start + r.End) / 2
+}
+
+// NearestRange returns the index of the range whose midpoint is closest to
+// result. Empty ranges never win, and an exact tie goes to the earlier range
+// in sorted order. When part of the domain belongs to
+// undisclosed bets it is treated as one more range after the visible ones,
+// and -1 is returned if its midpoint is the closest.
+func NearestRange(ranges []WinnerRange, result float64, hidden bool) int {
+	nearest, best := -1, math.Inf(1)
+	for i, r := range ranges {
+		if r.Start == r.End {
+			continue
+		}
+		if distance := math.Abs(r.Midpoint() - result); distance < best {
+			nearest, best = i, distance
+		}
+	}
+	if hidden && len(ranges) > 0 {
+		undisclosed := WinnerRange{Start: ranges[len(ranges)-1].End, End: ResultDomainMax}
+		if undisclosed.Start < undisclosed.End && math.Abs(undisclosed.Midpoint()-result) < best {
+			nearest = -1
+		}
+	}
+	return nearest
+}
+
+// nearestWinner returns the player owning the nearest range, or "" when
+// undisclosed bets are nearest
+func nearestWinner(ranges []WinnerRange, result float64, hidden bool) string {
+	if i := NearestRange(ranges, result, hidden); i >= 0 {
+		return ranges[i].Player
+	}
+	return ""
+}
+
+// SelectRoundWinner selects the winner under the game's winner rule,
+// honouring the round's declared range denominator, pre-computed shares and
+// fallback rule
+func SelectRoundWinner(game GameConfig, data RoundVerificationData, result float64) (string, error) {
+	if err := checkBetAmounts(data.Bets); err != nil {
+		return "", err
+	}
+	bets := data.WinningBets()
+	if len(bets) == 0 {
+		return "", nil
+	}
+
+	// A lone participant wins regardless of the result
+	if len(bets) == 1 && !data.HasHiddenShare() && !data.AmountsAreShares {
+		return bets[0].PlayerAddress, nil
+	}
+
+	ranges, err := data.WinnerRanges()
+	if err != nil {
+		return "", err
+	}
+	if game.WinnerRule == WinnerRuleNearest {
+		return nearestWinner(ranges, result, data.HasHiddenShare()), nil
+	}
+	return winnerForResult(ranges, result, data.HasHiddenShare(), data.fallbackRule())
+}
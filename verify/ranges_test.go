@@ -0,0 +1,371 @@
+package verify
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+func bets(amounts ...float64) []VerificationBet {
+	players := []string{"EQa", "EQb", "EQc", "EQd"}
+	list := make([]VerificationBet, len(amounts))
+	for i, amount := range amounts {
+		list[i] = VerificationBet{PlayerAddress: players[i], Amount: amount}
+	}
+	return list
+}
+
+func TestSelectRoundWinnerBetAmounts(t *testing.T) {
+	var betAmount *betAmountError
+	tests := []struct {
+		name    string
+		data    RoundVerificationData
+		result  float64
+		want    string
+		wantErr interface{}
+	}{
+		{name: "all zero", data: RoundVerificationData{Bets: bets(0, 0, 0)}, wantErr: &betAmount},
+		{name: "zero and non-zero", data: RoundVerificationData{Bets: bets(1, 0, 2)}, result: 50, wantErr: &betAmount},
+		{name: "negative with positive total", data: RoundVerificationData{Bets: bets(5, -1, 2)}, result: 50, wantErr: &betAmount},
+		{name: "single zero bet", data: RoundVerificationData{Bets: bets(0)}, wantErr: &betAmount},
+		{name: "single negative bet", data: RoundVerificationData{Bets: bets(-3)}, wantErr: &betAmount},
+		{
+			name:    "negative bet below minimum winning bet",
+			data:    RoundVerificationData{Bets: bets(5, -1), MinWinningBet: 1},
+			wantErr: &betAmount,
+		},
+		{name: "single bet", data: RoundVerificationData{Bets: bets(0.5)}, result: 99.999, want: "EQa"},
+		{name: "positive amounts", data: RoundVerificationData{Bets: bets(1, 1, 2)}, result: 50, want: "EQc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			winner, err := SelectRoundWinner(defaultGame(), tt.data, tt.result)
+			if tt.wantErr != nil {
+				if err == nil || !errors.As(err, tt.wantErr) {
+					t.Fatalf("SelectRoundWinner() = %q, %v; want %T", winner, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || winner != tt.want {
+				t.Fatalf("SelectRoundWinner() = %q, %v; want %q", winner, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyRoundRejectsBetAmounts(t *testing.T) {
+	tests := []struct {
+		name    string
+		amounts []float64
+		winner  string
+	}{
+		{name: "all zero", amounts: []float64{0, 0}, winner: "EQb"},
+		{name: "mixed zero", amounts: []float64{3, 0}, winner: "EQa"},
+		{name: "single zero", amounts: []float64{0}, winner: "EQa"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := VerifyRound(RoundVerificationData{Success: true, Bets: bets(tt.amounts...), Result: 10, WinnerAddress: tt.winner})
+			if err != nil {
+				t.Fatal(err)
+			}
+			check := report.Check(CheckWinner)
+			if check == nil || check.Passed || check.Error == "" {
+				t.Fatalf("winner check = %+v, want a failure naming the invalid amount", check)
+			}
+			if report.Passed {
+				t.Error("round with an invalid bet amount passed")
+			}
+		})
+	}
+}
+
+func TestWinnerRangesAdversarialAmounts(t *testing.T) {
+	// Summing each bet's percentage in turn ends these sets short of 100
+	// (99.99999999999997 for 0.1, 0.2, 0.3), leaving a gap only the
+	// fallback could fill; the prefix sums must close every range exactly.
+	tests := []struct {
+		name    string
+		amounts []float64
+	}{
+		{name: "tenths", amounts: []float64{0.1, 0.2, 0.3}},
+		{name: "thirds", amounts: []float64{1, 1, 1}},
+		{name: "sevenths", amounts: []float64{1, 1, 1, 1, 1, 1, 1}},
+		{name: "ten dimes", amounts: []float64{0.1, 0.1, 0.1, 0.1, 0.1, 0.1, 0.1, 0.1, 0.1, 0.1}},
+		{name: "tiny beside huge", amounts: []float64{1e-9, 1e9, 1e-9, 3}},
+		{name: "uneven thousandths", amounts: []float64{33.333, 33.333, 33.334}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var list []VerificationBet
+			for i, amount := range tt.amounts {
+				list = append(list, VerificationBet{PlayerAddress: fmt.Sprintf("EQ%02d", i), Amount: amount})
+			}
+			ranges, err := ComputeWinnerRanges(list)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ranges[0].Start != 0 || ranges[len(ranges)-1].End != ResultDomainMax {
+				t.Fatalf("ranges span [%v, %v), want [0, 100)", ranges[0].Start, ranges[len(ranges)-1].End)
+			}
+			for i := 1; i < len(ranges); i++ {
+				if ranges[i].Start != ranges[i-1].End {
+					t.Errorf("gap between range %d ending %v and range %d starting %v", i-1, ranges[i-1].End, i, ranges[i].Start)
+				}
+			}
+
+			data := RoundVerificationData{Bets: list}
+			probes := []float64{math.Nextafter(ResultDomainMax, 0)}
+			for _, r := range ranges {
+				probes = append(probes, r.Start, math.Nextafter(r.End, 0))
+			}
+			for _, result := range probes {
+				winner, err := SelectRoundWinner(defaultGame(), data, result)
+				if err != nil {
+					t.Errorf("result %v: %v", result, err)
+					continue
+				}
+				if i := holder(ranges, result); i < 0 || ranges[i].Player != winner {
+					t.Errorf("result %v won by %q, outside its range", result, winner)
+				}
+			}
+		})
+	}
+}
+
+// holder returns the index of the range holding result, or -1
+func holder(ranges []WinnerRange, result float64) int {
+	for i := range ranges {
+		if RangeHolds(ranges, i, result) {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestSelectRoundWinnerZeroResult(t *testing.T) {
+	tests := []struct {
+		name string
+		bets []VerificationBet
+		want string
+	}{
+		{name: "sorted", bets: bets(1, 2, 3), want: "EQa"},
+		{name: "first player listed last", bets: []VerificationBet{{PlayerAddress: "EQz", Amount: 5}, {PlayerAddress: "EQm", Amount: 1}}, want: "EQm"},
+		{name: "tiny first share", bets: bets(1e-9, 1e9), want: "EQa"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			winner, err := SelectRoundWinner(defaultGame(), RoundVerificationData{Bets: tt.bets}, 0)
+			if err != nil || winner != tt.want {
+				t.Fatalf("SelectRoundWinner(0) = %q, %v; want %q", winner, err, tt.want)
+			}
+			ranges, _ := ComputeWinnerRanges(tt.bets)
+			if ranges[0].Start != 0 || !ranges[0].Contains(0) {
+				t.Errorf("first range %+v does not hold 0", ranges[0])
+			}
+		})
+	}
+}
+
+func TestComputeWinnerRangesNonFinite(t *testing.T) {
+	tests := []struct {
+		name    string
+		amounts []float64
+		culprit string // address named in the error, or "" for no error
+	}{
+		{name: "two max floats overflow the total", amounts: []float64{math.MaxFloat64, math.MaxFloat64}, culprit: "EQb"},
+		{name: "max float after a bet", amounts: []float64{1, math.MaxFloat64, math.MaxFloat64}, culprit: "EQc"},
+		{name: "infinite amount", amounts: []float64{1, math.Inf(1)}, culprit: "EQb"},
+		{name: "NaN amount", amounts: []float64{math.NaN(), 1}, culprit: "EQa"},
+		{name: "one max float", amounts: []float64{math.MaxFloat64, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list := bets(tt.amounts...)
+			ranges, err := ComputeWinnerRanges(list)
+			if tt.culprit == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, r := range ranges {
+					if !finite(r.Start) || !finite(r.End) || !finite(r.Percent) {
+						t.Errorf("non-finite range %+v", r)
+					}
+				}
+				return
+			}
+			var rangeMath *rangeMathError
+			if !errors.As(err, &rangeMath) || rangeMath.bet.PlayerAddress != tt.culprit {
+				t.Fatalf("ComputeWinnerRanges() error = %v, want a range math error naming %s", err, tt.culprit)
+			}
+
+			report, _ := VerifyRound(RoundVerificationData{Success: true, Bets: list, Result: 50, WinnerAddress: "EQa"})
+			check := report.Check(CheckWinner)
+			if check.Passed || !strings.Contains(check.Error, "bet by "+tt.culprit) {
+				t.Errorf("winner check = %+v, want a failure naming %s", check, tt.culprit)
+			}
+		})
+	}
+}
+
+func TestRangeHoldsTopOfDomain(t *testing.T) {
+	ranges := []WinnerRange{
+		{Player: "EQa", Start: 0, End: 40},
+		{Player: "EQb", Start: 40, End: 100},
+		{Player: "EQc", Start: 100, End: 100},
+	}
+	tests := []struct {
+		name   string
+		ranges []WinnerRange
+		result float64
+		want   int
+	}{
+		{name: "100 in the last range", ranges: ranges[:2], result: 100, want: 1},
+		{name: "100 skips a trailing empty range", ranges: ranges, result: 100, want: 1},
+		{name: "just below 100", ranges: ranges[:2], result: math.Nextafter(100, 0), want: 1},
+		{name: "boundary is half-open", ranges: ranges[:2], result: 40, want: 1},
+		{name: "above the domain", ranges: ranges[:2], result: 100.001, want: -1},
+		{name: "ranges short of 100", ranges: []WinnerRange{{Player: "EQa", Start: 0, End: 60}}, result: 100, want: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := holder(tt.ranges, tt.result); got != tt.want {
+				t.Errorf("range holding %v = %d, want %d", tt.result, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectRoundWinnerTopResult(t *testing.T) {
+	tests := []struct {
+		name string
+		data RoundVerificationData
+		want string
+	}{
+		{name: "last sorted player", data: RoundVerificationData{Bets: bets(3, 2, 1)}, want: "EQc"},
+		{name: "last player listed first", data: RoundVerificationData{Bets: []VerificationBet{{PlayerAddress: "EQz", Amount: 1}, {PlayerAddress: "EQm", Amount: 5}}}, want: "EQz"},
+		{name: "undisclosed share holds the top", data: RoundVerificationData{Bets: bets(1, 1), RangeDenominator: 4}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			winner, err := SelectRoundWinner(defaultGame(), tt.data, ResultDomainMax)
+			if err != nil || winner != tt.want {
+				t.Fatalf("SelectRoundWinner(100) = %q, %v; want %q", winner, err, tt.want)
+			}
+		})
+	}
+	if err := CheckResultDomain(ResultDomainMax); err != nil {
+		t.Errorf("CheckResultDomain(100) = %v, want nil", err)
+	}
+	if err := CheckResultDomain(100.001); err == nil {
+		t.Error("CheckResultDomain(100.001) accepted a result above the domain")
+	}
+}
+
+func TestSelectRoundWinnerFallbackOverride(t *testing.T) {
+	// a result past 100 lies outside every range
+	round := RoundVerificationData{Bets: bets(1, 1)}
+	tests := []struct {
+		name     string
+		declared string
+		override string
+		want     string
+		wantErr  bool
+	}{
+		{name: "no rule fails", wantErr: true},
+		{name: "declared rule", declared: FallbackFirst, want: "EQa"},
+		{name: "override replaces the declared rule", declared: FallbackFirst, override: FallbackLast, want: "EQb"},
+		{name: "override without a declared rule", override: FallbackLast, want: "EQb"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := round
+			data.FallbackRule, data.FallbackOverride = tt.declared, tt.override
+			got, err := SelectRoundWinner(defaultGame(), data, 100.5)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("winner %q, error %v; want %q (error %v)", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWinProbabilities(t *testing.T) {
+	tests := []struct {
+		name string
+		bets []VerificationBet
+		want map[string]float64 // nil when no probabilities are returned
+		// shares compares with the ranges of the bets read as pre-computed
+		// shares of the domain
+		shares bool
+	}{
+		{name: "single bet", bets: bets(2.5), want: map[string]float64{"EQa": 1}},
+		{name: "proportional to amount", bets: bets(1, 3), want: map[string]float64{"EQa": 0.25, "EQb": 0.75}},
+		{
+			name: "several bets by one player are summed",
+			bets: append(bets(1, 2), VerificationBet{PlayerAddress: "EQa", Amount: 1}),
+			want: map[string]float64{"EQa": 0.5, "EQb": 0.5},
+		},
+		{
+			// WinProbabilities applies no game profile, so a bet below a
+			// minimum winning bet still counts
+			name: "bet below a minimum winning bet",
+			bets: bets(0.01, 9.99),
+			want: map[string]float64{"EQa": 0.001, "EQb": 0.999},
+		},
+		{name: "share amounts", bets: bets(20, 30, 50), want: map[string]float64{"EQa": 0.2, "EQb": 0.3, "EQc": 0.5}, shares: true},
+		{name: "no bets"},
+		{name: "zero amount", bets: bets(1, 0)},
+		{name: "negative amount", bets: bets(2, -1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := WinProbabilities(tt.bets)
+			if tt.want == nil {
+				if got != nil {
+					t.Fatalf("got %v, want nil", got)
+				}
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			sum := 0.0
+			for player, p := range got {
+				if math.Abs(p-tt.want[player]) > 1e-12 {
+					t.Errorf("%s: probability %v, want %v", player, p, tt.want[player])
+				}
+				sum += p
+			}
+			if math.Abs(sum-1) > 1e-12 {
+				t.Errorf("probabilities sum to %v, not 1", sum)
+			}
+
+			// Each player's probability is the width of their ranges, and
+			// the last range, half-open like the others, also holds 100.000
+			ranges, err := ComputeWinnerRanges(tt.bets)
+			if tt.shares {
+				ranges, err = RoundVerificationData{Bets: tt.bets, AmountsAreShares: true}.WinnerRanges()
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !RangeHolds(ranges, len(ranges)-1, ResultDomainMax) {
+				t.Errorf("last range %+v does not hold %.3f", ranges[len(ranges)-1], ResultDomainMax)
+			}
+			widths := map[string]float64{}
+			for _, r := range ranges {
+				widths[r.Player] += (r.End - r.Start) / ResultDomainMax
+			}
+			for player, width := range widths {
+				if math.Abs(width-got[player]) > 1e-9 {
+					t.Errorf("%s: ranges cover %v of the domain, probability %v", player, width, got[player])
+				}
+			}
+		})
+	}
+}
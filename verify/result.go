@@ -0,0 +1,94 @@
+package verify
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// HashString returns the lowercase hex SHA-256 of str
+func HashString(str string) string {
+	h := sha256.Sum256([]byte(str))
+	return hex.EncodeToString(h[:])
+}
+
+func calculateResult(serverSeed, clientSeed string, roundNumber int, previousHash string) float64 {
+	return CalculateResultTrace(defaultGame(), serverSeed, clientSeed, roundNumber, previousHash, nil)
+}
+
+// CalculateResultTrace computes the round result under the given game
+// profile, recording the HMAC input, output and modular reduction when trace
+// is non-nil.
+func CalculateResultTrace(game GameConfig, serverSeed, clientSeed string, roundNumber int, previousHash string, trace *Trace) float64 {
+	combined := game.Message(serverSeed, clientSeed, roundNumber, previousHash)
+	trace.AddBytes("result.hmac_key", []byte(serverSeed))
+	trace.AddBytes("result.combined", []byte(combined))
+	d := DeriveResult(game, serverSeed, combined)
+	trace.Add("result.hmac_"+game.HashAlgorithm, d.HMACHex)
+	trace.Add("result.hmac_int", d.HMACDecimal)
+	trace.Add("result.modulus", strconv.FormatInt(d.Modulus, 10))
+	trace.Add("result.mod", strconv.FormatInt(d.Remainder, 10))
+	trace.Add("result.value", fmt.Sprintf("%d / %g = %.3f", d.Remainder, d.Divisor, d.Result))
+	return d.Result
+}
+
+// ResultDerivation is every integer step from the HMAC to the result, for
+// checking the modular arithmetic independently
+type ResultDerivation struct {
+	HMACHex     string  `json:"hmac_hex"`
+	HMACDecimal string  `json:"hmac_decimal"`
+	Modulus     int64   `json:"modulus"`
+	Remainder   int64   `json:"remainder"`
+	Divisor     float64 `json:"divisor"`
+	Result      float64 `json:"result"`
+}
+
+// DeriveResult reads the HMAC of message as a big-endian unsigned integer,
+// reduces it modulo the game's modulus and divides by its divisor
+func DeriveResult(game GameConfig, serverSeed, message string) ResultDerivation {
+	h := hmac.New(game.NewHash, []byte(serverSeed))
+	h.Write([]byte(message))
+	hash := h.Sum(nil)
+
+	hashInt := new(big.Int).SetBytes(hash)
+	remainder := new(big.Int).Mod(hashInt, big.NewInt(game.Modulus)).Int64()
+	return ResultDerivation{
+		HMACHex:     hex.EncodeToString(hash),
+		HMACDecimal: hashInt.String(),
+		Modulus:     game.Modulus,
+		Remainder:   remainder,
+		Divisor:     game.Divisor,
+		Result:      float64(remainder) / game.Divisor,
+	}
+}
+
+// Winner ranges are percentages of the pot covering [ResultDomainMin,
+// ResultDomainMax]. calculateResult can yield up to 100.000 (modulus 100001),
+// which the last range holds (see RangeHolds), so only a result outside this
+// closed interval cannot fall in any range.
+const (
+	ResultDomainMin = 0.0
+	ResultDomainMax = 100.0
+)
+
+// CheckResultDomain reports a result that no winner range can contain
+func CheckResultDomain(result float64) error {
+	if math.IsNaN(result) || result < ResultDomainMin || result > ResultDomainMax {
+		return fmt.Errorf("%.3f is outside [%.0f, %.0f], so no player's range can contain it",
+			result, ResultDomainMin, ResultDomainMax)
+	}
+	return nil
+}
+
+// ShortHash abbreviates a hash to its first 16 characters for display.
+// Shorter values, e.g. a malformed claimed hash, are returned unchanged.
+func ShortHash(hash string) string {
+	if len(hash) <= 16 {
+		return hash
+	}
+	return hash[:16] + "..."
+}
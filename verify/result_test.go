@@ -0,0 +1,49 @@
+package verify
+
+import "testing"
+
+func TestShortHash(t *testing.T) {
+	tests := []struct {
+		hash string
+		want string
+	}{
+		{hash: "", want: ""},
+		{hash: "abcd", want: "abcd"},
+		{hash: "0123456789abcdef", want: "0123456789abcdef"},
+		{hash: "0123456789abcdef0", want: "0123456789abcdef..."},
+		{hash: "e72430b6bf09ac29e97d6e15d9dd52062766cc26fd987d786b354f3b1c39ae03", want: "e72430b6bf09ac29..."},
+	}
+	for _, tt := range tests {
+		if got := ShortHash(tt.hash); got != tt.want {
+			t.Errorf("ShortHash(%q) = %q, want %q", tt.hash, got, tt.want)
+		}
+	}
+}
+
+func TestVerifyRoundShortValues(t *testing.T) {
+	tests := []struct {
+		name string
+		data RoundVerificationData
+	}{
+		{name: "4-char hashes", data: RoundVerificationData{
+			Success: true, ServerSeed: "abcd", ServerHash: "abcd", ClientSeed: "abcd",
+			Bets: bets(1, 2), WinnerAddress: "EQb", Result: 50,
+		}},
+		{name: "empty addresses", data: RoundVerificationData{
+			Success: true, ServerSeed: "abcd", ServerHash: "", ClientSeed: "",
+			Bets: []VerificationBet{{Amount: 1}, {Amount: 2}}, Result: 50,
+		}},
+		{name: "everything empty", data: RoundVerificationData{Success: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := VerifyRound(tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.Passed {
+				t.Errorf("round with mismatched seeds passed")
+			}
+		})
+	}
+}
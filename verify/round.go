@@ -0,0 +1,91 @@
+package verify
+
+// VerificationBet represents a bet for verification
+type VerificationBet struct {
+	PlayerAddress string  `json:"player_address"`
+	Amount        float64 `json:"amount"`
+	GiftID        string  `json:"gift_id"`
+	// TxHash is the on-chain transaction that paid for the bet, when the
+	// API discloses it; it is not part of the client seed
+	TxHash string `json:"tx_hash,omitempty"`
+}
+
+// RoundVerificationData contains all data needed for verification
+type RoundVerificationData struct {
+	Success       bool              `json:"success"`
+	RoundID       string            `json:"round_id"`
+	RoundNumber   int               `json:"round_number"`
+	ServerSeed    string            `json:"server_seed"`
+	ServerHash    string            `json:"server_hash"`
+	ClientSeed    string            `json:"client_seed"`
+	PreviousHash  string            `json:"previous_hash"`
+	Bets          []VerificationBet `json:"bets"`
+	Result        float64           `json:"result"`
+	WinnerAddress string            `json:"winner_address"`
+	TotalPot      float64           `json:"total_pot"`
+	Error         string            `json:"error,omitempty"`
+	// SeedRoot is the Merkle root of a batch of server seeds committed at
+	// once; SeedProof holds the sibling hashes from the seed's leaf, at
+	// SeedIndex in the batch, up to the root
+	SeedRoot  string   `json:"seed_root,omitempty"`
+	SeedIndex int      `json:"seed_index,omitempty"`
+	SeedProof []string `json:"seed_proof,omitempty"`
+	// CrashMultiplier is the secondary crash game outcome derived from the
+	// result, present only for rounds that feed a crash game
+	CrashMultiplier float64 `json:"crash_multiplier,omitempty"`
+	// ShuffleInput and ShuffleOutput are a list shuffled with the round's
+	// seeds and its claimed order, for games sharing the jackpot RNG
+	ShuffleInput  []string `json:"shuffle_input,omitempty"`
+	ShuffleOutput []string `json:"shuffle_output,omitempty"`
+
+	// CreatedAt is the round's RFC 3339 creation time, when the API sends it
+	CreatedAt string `json:"created_at,omitempty"`
+	// BeaconRound and BeaconValue anchor the client seed to a drand
+	// randomness beacon round; BeaconSignature is the round's BLS signature,
+	// whose SHA-256 is the value
+	BeaconRound     int64  `json:"beacon_round,omitempty"`
+	BeaconValue     string `json:"beacon_value,omitempty"`
+	BeaconSignature string `json:"beacon_signature,omitempty"`
+	// Payout is what the winner was paid and HouseCut what the operator
+	// kept of the pot, when the API discloses them
+	Payout   float64 `json:"payout,omitempty"`
+	HouseCut float64 `json:"house_cut,omitempty"`
+	// Cancelled rounds were refunded: seeds, bets and result are published
+	// but nobody won, so WinnerAddress must be empty
+	Cancelled bool `json:"cancelled,omitempty"`
+
+	// BetShards and ShardRoots describe a bet list split into shards; when
+	// bets is absent it is filled from the shards
+	BetShards  [][]VerificationBet `json:"bet_shards,omitempty"`
+	ShardRoots []string            `json:"shard_roots,omitempty"`
+
+	// Pagination of the bet list by the verify endpoint
+	NextCursor string `json:"next_cursor,omitempty"`
+	TotalBets  int    `json:"total_bets,omitempty"`
+
+	// Progressive jackpot accounting, present only for progressive games
+	StartingPot float64 `json:"starting_pot,omitempty"`
+	NewBets     float64 `json:"new_bets,omitempty"`
+	Rollover    float64 `json:"rollover,omitempty"`
+
+	// RangeDenominator, when set, is the server-declared total the ranges
+	// are percentages of, covering hidden or house bets beyond the visible ones
+	RangeDenominator float64 `json:"range_denominator,omitempty"`
+
+	// FallbackRule is the declared winner for a result outside every range:
+	// "last" or "first" sorted player, or "fail" (the default)
+	FallbackRule string `json:"fallback_rule,omitempty"`
+	// FallbackOverride replaces FallbackRule when set, from --fallback-rule;
+	// the declared rule is kept so the round still serializes as published
+	FallbackOverride string `json:"-"`
+
+	// AmountsAreShares marks bet amounts as pre-computed percentages of the
+	// domain rather than TON, set from --amounts-are-shares
+	AmountsAreShares bool `json:"-"`
+	// RangeOrder is the game's range order, set from the game profile; see
+	// GameConfig.RangeOrder
+	RangeOrder string `json:"-"`
+	// MinWinningBet is the game's minimum winning bet, set from the game
+	// profile; see GameConfig.MinWinningBet
+	MinWinningBet float64 `json:"-"`
+}
@@ -0,0 +1,93 @@
+package verify
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+)
+
+// Backends that split a round's bets into shards publish them as
+// bet_shards, optionally with the root each shard hashed to. A shard's root
+// is the client seed of that shard alone (ComputeClientSeed applied to its
+// bets), and the round's client seed is the hash of the lowercase hex roots
+// concatenated in shard order, using the game's client seed hash.
+
+// isSharded reports whether the round publishes its bets as shards
+func (d RoundVerificationData) isSharded() bool {
+	return len(d.BetShards) > 0 || len(d.ShardRoots) > 0
+}
+
+// flattenShards returns every sharded bet in shard order
+func flattenShards(shards [][]VerificationBet) []VerificationBet {
+	var bets []VerificationBet
+	for _, shard := range shards {
+		bets = append(bets, shard...)
+	}
+	return bets
+}
+
+// combineShardRoots hashes the concatenated shard roots into the client seed
+func combineShardRoots(game GameConfig, roots []string, trace *Trace) string {
+	h, label := game.clientSeedHash()
+	for i, root := range roots {
+		h.Write([]byte(root))
+		trace.Add(fmt.Sprintf("client_seed.shard[%d].root", i), root)
+	}
+	clientSeed := hex.EncodeToString(h.Sum(nil))
+	trace.Add(label, clientSeed)
+	return clientSeed
+}
+
+// shardedClientSeedCheck recomputes the client seed of a sharded round. When
+// bet shards are given their roots are recomputed and must match any claimed
+// roots; with claimed roots alone only the combination step is verified.
+func shardedClientSeedCheck(data RoundVerificationData, opts Options, trace *Trace) Check {
+	check := Check{Name: CheckClientSeed, Actual: data.ClientSeed}
+
+	roots := data.ShardRoots
+	if len(data.BetShards) > 0 {
+		if len(data.ShardRoots) > 0 && len(data.ShardRoots) != len(data.BetShards) {
+			check.Error = fmt.Sprintf("%d shard roots declared for %d bet shards", len(data.ShardRoots), len(data.BetShards))
+			return check
+		}
+		if !sameBets(data.Bets, flattenShards(data.BetShards)) {
+			check.Error = "bets and bet_shards list different bets"
+			return check
+		}
+		roots = make([]string, len(data.BetShards))
+		for i, shard := range data.BetShards {
+			roots[i] = computeClientSeedTrace(opts.Game, shard, nil)
+			if len(data.ShardRoots) > 0 && !opts.HexMatches(roots[i], data.ShardRoots[i]) {
+				check.Error = fmt.Sprintf("shard %d root mismatch: calculated %s, claimed %s",
+					i, ShortHash(roots[i]), ShortHash(data.ShardRoots[i]))
+				return check
+			}
+		}
+	}
+
+	check.Expected = combineShardRoots(opts.Game, roots, trace)
+	check.Passed = opts.HexMatches(check.Expected, data.ClientSeed)
+	return check
+}
+
+// sameBets reports whether two bet lists hold the same bets, ignoring order
+func sameBets(a, b []VerificationBet) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	serialized := func(bets []VerificationBet) []string {
+		out := make([]string, len(bets))
+		for i, bet := range bets {
+			out[i] = string(AppendBet(nil, bet))
+		}
+		sort.Strings(out)
+		return out
+	}
+	sa, sb := serialized(a), serialized(b)
+	for i := range sa {
+		if sa[i] != sb[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,93 @@
+package verify
+
+import (
+	"crypto/hmac"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// shuffleSuffix is appended to the HMAC message, followed by a block
+// counter, to draw the shuffle's random stream
+const shuffleSuffix = ":shuffle:"
+
+// shuffleStream yields big-endian uint64 draws from consecutive HMAC blocks,
+// each keyed by the server seed over the round message, the shuffle suffix
+// and the block number from 0
+type shuffleStream struct {
+	game    GameConfig
+	key     []byte
+	message string
+	block   int
+	pending []byte
+	trace   *Trace
+}
+
+func (s *shuffleStream) next() uint64 {
+	if len(s.pending) < 8 {
+		h := hmac.New(s.game.NewHash, s.key)
+		h.Write([]byte(s.message + strconv.Itoa(s.block)))
+		s.pending = h.Sum(nil)
+		s.trace.Add(fmt.Sprintf("shuffle.hmac_%s_%d", s.game.HashAlgorithm, s.block), hex.EncodeToString(s.pending))
+		s.block++
+	}
+	draw := binary.BigEndian.Uint64(s.pending)
+	s.pending = s.pending[8:]
+	return draw
+}
+
+// below returns a uniform draw in [0, n), rejecting the draws at the top of
+// the uint64 range that would bias the modulo
+func (s *shuffleStream) below(n uint64) uint64 {
+	limit := math.MaxUint64 - math.MaxUint64%n
+	for {
+		if draw := s.next(); draw < limit {
+			return draw % n
+		}
+	}
+}
+
+// shuffleItems reproduces a Fisher-Yates shuffle seeded by the round: for i
+// from the last position down to 1, item i is swapped with item j, a draw
+// in [0, i]. The input is not modified.
+func shuffleItems(game GameConfig, data RoundVerificationData, items []string, trace *Trace) []string {
+	shuffled := make([]string, len(items))
+	copy(shuffled, items)
+	stream := &shuffleStream{
+		game:    game,
+		key:     []byte(data.ServerSeed),
+		message: game.Message(data.ServerSeed, data.ClientSeed, data.RoundNumber, data.PreviousHash) + shuffleSuffix,
+		trace:   trace,
+	}
+	for i := len(shuffled) - 1; i > 0; i-- {
+		j := stream.below(uint64(i + 1))
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	}
+	return shuffled
+}
+
+// shuffleCheck reproduces the round's shuffle of ShuffleInput and compares it
+// with the claimed ShuffleOutput, naming the first position that differs
+func shuffleCheck(game GameConfig, data RoundVerificationData, trace *Trace) Check {
+	computed := shuffleItems(game, data, data.ShuffleInput, trace)
+	check := Check{
+		Name:     CheckShuffle,
+		Expected: strings.Join(computed, ","),
+		Actual:   strings.Join(data.ShuffleOutput, ","),
+	}
+	if len(computed) != len(data.ShuffleOutput) {
+		check.Error = fmt.Sprintf("claimed order has %d items, the input %d", len(data.ShuffleOutput), len(computed))
+		return check
+	}
+	for i := range computed {
+		if computed[i] != data.ShuffleOutput[i] {
+			check.Error = fmt.Sprintf("position %d holds %q, expected %q", i, data.ShuffleOutput[i], computed[i])
+			return check
+		}
+	}
+	check.Passed = true
+	return check
+}
@@ -0,0 +1,60 @@
+package verify
+
+import (
+	"crypto/hmac"
+	"encoding/hex"
+	"math"
+	"math/big"
+)
+
+// TieEpsilon is how close a result must be to a range boundary to count as
+// landing exactly on it
+const TieEpsilon = 1e-9
+
+// TieBreakSuffix is appended to the HMAC message for the secondary draw
+const TieBreakSuffix = ":tiebreak"
+
+// TieBreak records how a result landing on a range boundary was resolved
+type TieBreak struct {
+	Boundary float64 `json:"boundary"`
+	Lower    string  `json:"lower_player"`
+	Upper    string  `json:"upper_player"`
+	Draw     string  `json:"draw"`
+	Winner   string  `json:"winner"`
+}
+
+// findBoundaryTie reports the two players whose shared boundary the result
+// lands on, if any. The outer edges 0 and 100 are not shared.
+func findBoundaryTie(ranges []WinnerRange, result float64) (lower, upper WinnerRange, ok bool) {
+	for i := 1; i < len(ranges); i++ {
+		if math.Abs(result-ranges[i].Start) <= TieEpsilon {
+			return ranges[i-1], ranges[i], true
+		}
+	}
+	return WinnerRange{}, WinnerRange{}, false
+}
+
+// resolveTieBreak draws a second HMAC from the same seeds with the tie-break
+// suffix appended to the message. An even draw picks the player below the
+// boundary, an odd draw the player above it.
+func resolveTieBreak(game GameConfig, data RoundVerificationData, lower, upper WinnerRange, trace *Trace) *TieBreak {
+	message := game.Message(data.ServerSeed, data.ClientSeed, data.RoundNumber, data.PreviousHash) + TieBreakSuffix
+	trace.AddBytes("tiebreak.combined", []byte(message))
+	h := hmac.New(game.NewHash, []byte(data.ServerSeed))
+	h.Write([]byte(message))
+	draw := h.Sum(nil)
+	trace.Add("tiebreak.hmac_"+game.HashAlgorithm, hex.EncodeToString(draw))
+
+	tie := &TieBreak{
+		Boundary: upper.Start,
+		Lower:    lower.Player,
+		Upper:    upper.Player,
+		Draw:     hex.EncodeToString(draw),
+		Winner:   lower.Player,
+	}
+	if new(big.Int).SetBytes(draw).Bit(0) == 1 {
+		tie.Winner = upper.Player
+	}
+	trace.Add("tiebreak.winner", tie.Winner)
+	return tie
+}
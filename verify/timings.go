@@ -0,0 +1,76 @@
+package verify
+
+import "time"
+
+// Timings breaks a verification down by step, for profiling large rounds
+// and the serve mode
+type Timings struct {
+	// ParseMS is the time spent decoding the round data, when the caller
+	// measured it
+	ParseMS float64 `json:"parse_ms,omitempty"`
+	// StepsMS is the time spent in each check, keyed by check name
+	StepsMS map[string]float64 `json:"steps_ms"`
+	// TotalMS is the whole verification, excluding parsing
+	TotalMS float64 `json:"total_ms"`
+	HashOps HashOps `json:"hash_ops"`
+}
+
+// HashOps counts the hashing and scanning work behind a verification
+type HashOps struct {
+	// CommitHashes is the number of server seed commitments recomputed
+	CommitHashes int `json:"commit_hashes"`
+	// ClientSeedBytes is the number of serialized bet bytes hashed into
+	// the client seed
+	ClientSeedBytes int `json:"client_seed_bytes"`
+	// HMACs counts the result draw, a tie-break draw and the --show-bigint
+	// derivation
+	HMACs int `json:"hmacs"`
+	// RangesScanned is the number of winner ranges searched for the result
+	RangesScanned int `json:"ranges_scanned"`
+}
+
+// record adds the time spent in a check
+func (t *Timings) record(name string, elapsed time.Duration) {
+	if t == nil {
+		return
+	}
+	if t.StepsMS == nil {
+		t.StepsMS = map[string]float64{}
+	}
+	t.StepsMS[name] += DurationMS(elapsed)
+}
+
+// countHashOps derives the hashing work from the checks that ran
+func countHashOps(data RoundVerificationData, result *Report) HashOps {
+	var ops HashOps
+	performed := func(name string) bool {
+		check := result.Check(name)
+		return check != nil && !check.Skipped
+	}
+	if performed(CheckServerHash) {
+		ops.CommitHashes = 1
+	}
+	if performed(CheckClientSeed) {
+		var buf []byte
+		for _, bet := range data.Bets {
+			ops.ClientSeedBytes += len(AppendBet(buf[:0], bet))
+		}
+	}
+	if performed(CheckResult) {
+		ops.HMACs = 1
+		if result.Derivation != nil {
+			ops.HMACs++
+		}
+	}
+	if performed(CheckWinner) && !data.Cancelled {
+		ops.RangesScanned = len(data.Bets)
+		if result.TieBreak != nil {
+			ops.HMACs++
+		}
+	}
+	return ops
+}
+
+func DurationMS(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,35 @@
+package verify
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+)
+
+// TraceStep is a single intermediate value recorded during verification
+type TraceStep struct {
+	Label string `json:"label"`
+	Value string `json:"value"`
+}
+
+// Trace collects intermediate values in the order they were computed so the
+// whole derivation can be replayed independently. A nil *Trace records nothing.
+type Trace struct {
+	Steps []TraceStep `json:"steps"`
+}
+
+// Add records a value under the given label
+func (t *Trace) Add(label, value string) {
+	if t == nil {
+		return
+	}
+	t.Steps = append(t.Steps, TraceStep{Label: label, Value: value})
+}
+
+// AddBytes records raw bytes as hex followed by their quoted text form
+func (t *Trace) AddBytes(label string, b []byte) {
+	if t == nil {
+		return
+	}
+	t.Add(label, fmt.Sprintf("hex:%s text:%s", hex.EncodeToString(b), strconv.Quote(string(b))))
+}
@@ -0,0 +1,543 @@
+// Package verify recomputes a LazyBox jackpot round from its revealed seeds
+// and bets and checks every value the server claimed: the server hash, the
+// client seed, the result and the winner, plus the optional checks a game
+// profile or the options enable. It only computes; the command in the
+// repository root fetches rounds and renders the reports.
+package verify
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Names of the individual checks performed on a round
+const (
+	CheckServerHash = "server_hash"
+	CheckClientSeed = "client_seed"
+	CheckResult     = "result"
+	CheckWinner     = "winner"
+
+	CheckExpectedWinner  = "expected_winner"
+	CheckSeedStrength    = "seed_strength"
+	CheckBetAmounts      = "bet_amounts"
+	CheckCrashMultiplier = "crash_multiplier"
+	CheckBeacon          = "beacon"
+	CheckShuffle         = "shuffle"
+	CheckUniqueGifts     = "unique_gifts"
+)
+
+// Check is the outcome of a single verification step
+type Check struct {
+	Name     string `json:"name"`
+	Passed   bool   `json:"passed"`
+	Skipped  bool   `json:"skipped,omitempty"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Report is the structured outcome of verifying one round
+type Report struct {
+	RoundID            string               `json:"round_id"`
+	RoundNumber        int                  `json:"round_number"`
+	Game               string               `json:"game"`
+	Passed             bool                 `json:"passed"`
+	Partial            bool                 `json:"partial,omitempty"`
+	Aborted            bool                 `json:"aborted,omitempty"`
+	Cancelled          bool                 `json:"cancelled,omitempty"`
+	TotalPot           float64              `json:"total_pot"`
+	ClaimedResult      float64              `json:"claimed_result"`
+	ClaimedWinner      string               `json:"claimed_winner"`
+	Checks             []Check              `json:"checks"`
+	ComputedClientSeed string               `json:"computed_client_seed"`
+	ComputedResult     float64              `json:"computed_result"`
+	ComputedWinner     string               `json:"computed_winner"`
+	WinnerRule         string               `json:"winner_rule,omitempty"`
+	HouseAddresses     []string             `json:"house_addresses,omitempty"`
+	HouseWon           bool                 `json:"house_won,omitempty"`
+	TieBreak           *TieBreak            `json:"tie_break,omitempty"`
+	ClaimedRange       *RangeAssertion      `json:"claimed_range,omitempty"`
+	RangeCoverage      *RangeCoverage       `json:"range_coverage,omitempty"`
+	WinnerRanges       []WinnerRange        `json:"winner_ranges,omitempty"`
+	Derivation         *ResultDerivation    `json:"derivation,omitempty"`
+	Alerts             []string             `json:"alerts,omitempty"`
+	Trace              *Trace               `json:"trace,omitempty"`
+	Timings            *Timings             `json:"timings,omitempty"`
+	Commitment         *PublishedCommitment `json:"commitment,omitempty"`
+	ShareURL           string               `json:"share_url,omitempty"`
+	VerifierVersion    string               `json:"verifier_version"`
+	Duration           time.Duration        `json:"-"`
+}
+
+// Options controls how VerifyRoundWithOptions recomputes a round
+type Options struct {
+	Game  GameConfig
+	Trace bool
+	// HexCaseInsensitive lowercases claimed hashes before comparing them.
+	// Hashed bytes are unaffected; only the comparison is relaxed.
+	HexCaseInsensitive bool
+	// ExpectWinner pins the address the round must have been won by,
+	// independently of the round's internal consistency
+	ExpectWinner string
+	// MinSeedBits fails rounds whose revealed server seed has less estimated
+	// entropy than this; zero disables the check
+	MinSeedBits float64
+	// Partial skips checks whose input data the operator withheld instead of
+	// failing them
+	Partial bool
+	// MaxBet fails rounds with any single bet above this amount; zero
+	// disables the cap (bets are always checked against the total pot)
+	MaxBet float64
+	// AmountsAreShares treats bet amounts as percentage shares; see
+	// RoundVerificationData.AmountsAreShares
+	AmountsAreShares bool
+	// FallbackRule, when set, overrides the round's declared fallback rule
+	FallbackRule string
+	// ShowBigInt records the integer steps of the result derivation
+	ShowBigInt bool
+	// Commitment, when set, replaces the round's server_hash in check #1
+	// with the commitment published before the round
+	Commitment *PublishedCommitment
+	// Beacons are the round's drand beacon as served by each --drand relay
+	Beacons []DrandBeacon
+	// Timings records how long each check took and the hashing work done
+	Timings bool
+	// Hooks are called as each check runs
+	Hooks VerifyHooks
+}
+
+// HexMatches compares a computed lowercase hex value with a claimed one
+func (o Options) HexMatches(computed, claimed string) bool {
+	if o.HexCaseInsensitive {
+		claimed = strings.ToLower(claimed)
+	}
+	return computed == claimed
+}
+
+// Apply returns a copy of data carrying the settings of o that shape its
+// ranges and winner: the game's range order and minimum winning bet, share
+// amounts and a fallback rule override. Only fields that are not serialized
+// change, so the copy still hashes as the published round. Verification
+// applies them itself; callers rendering ranges next to a result use it so
+// both agree.
+func (o Options) Apply(data RoundVerificationData) RoundVerificationData {
+	if o.AmountsAreShares {
+		data.AmountsAreShares = true
+	}
+	data.FallbackOverride = o.FallbackRule
+	data.RangeOrder = o.Game.RangeOrder
+	data.MinWinningBet = o.Game.MinWinningBet
+	return data
+}
+
+// sameHex compares two claimed hex values, neither of which was computed
+// here, ignoring case only when HexCaseInsensitive is set
+func (o Options) sameHex(a, b string) bool {
+	if o.HexCaseInsensitive {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
+// Check returns the named check, or nil if it was not performed
+func (r *Report) Check(name string) *Check {
+	for i := range r.Checks {
+		if r.Checks[i].Name == name {
+			return &r.Checks[i]
+		}
+	}
+	return nil
+}
+
+// CheckPassed reports whether the named check was performed and passed, so
+// callers can read off e.g. the server hash, client seed, result and winner
+// outcomes one by one
+func (r *Report) CheckPassed(name string) bool {
+	check := r.Check(name)
+	return check != nil && check.Passed && !check.Skipped
+}
+
+// FailedChecks lists the names of the checks that did not pass
+func (r *Report) FailedChecks() []string {
+	var failed []string
+	for _, check := range r.Checks {
+		if !check.Passed && !check.Skipped {
+			failed = append(failed, check.Name)
+		}
+	}
+	return failed
+}
+
+// SkippedChecks lists the names of the checks skipped for lack of data or
+// after an abort
+func (r *Report) SkippedChecks() []string {
+	var skipped []string
+	for _, check := range r.Checks {
+		if check.Skipped {
+			skipped = append(skipped, check.Name)
+		}
+	}
+	return skipped
+}
+
+func (r *Report) addCheck(check Check) {
+	r.Checks = append(r.Checks, check)
+	if !check.Passed && !check.Skipped {
+		r.Passed = false
+	}
+}
+
+// VerifyRound verifies a round already decoded in memory against the
+// default game profile and returns the report without printing. Each
+// comparison is in Checks (CheckPassed gives its outcome by name) and the
+// recomputed values in the Computed fields. An error means the round could not be
+// verified at all: the API returned an error payload instead of its data.
+func VerifyRound(data RoundVerificationData) (*Report, error) {
+	if !data.Success {
+		return nil, fmt.Errorf("round %s has no verification data: %s", data.RoundID, data.Error)
+	}
+	return VerifyRoundWithOptions(data, Options{Game: defaultGame()}), nil
+}
+
+// VerifyRoundWithOptions recomputes every derived value of a round under
+// opts and compares it with what the server claimed, without printing
+// anything. This is what the command-line verifier calls.
+func VerifyRoundWithOptions(data RoundVerificationData, opts Options) *Report {
+	start := time.Now()
+	data = opts.Apply(data)
+	result := &Report{
+		RoundID:        data.RoundID,
+		RoundNumber:    data.RoundNumber,
+		Game:           opts.Game.Name,
+		Passed:         true,
+		Cancelled:      data.Cancelled,
+		WinnerRule:     opts.Game.WinnerRule,
+		HouseAddresses: opts.Game.HouseAddresses,
+
+		TotalPot:      data.TotalPot,
+		ClaimedResult: data.Result,
+		ClaimedWinner: data.WinnerAddress,
+
+		VerifierVersion: VersionString(),
+	}
+	if opts.Trace {
+		result.Trace = &Trace{}
+	}
+	if opts.Timings {
+		result.Timings = &Timings{}
+	}
+
+	// run performs a check unless --partial is set and the round withholds
+	// the data it needs, in which case the check is recorded as skipped.
+	// Once a hook aborts, the remaining checks are recorded as skipped
+	// without calling the hooks again.
+	withheld := false
+	run := func(name string, perform func() Check) {
+		if result.Aborted {
+			result.addCheck(Check{Name: name, Skipped: true, Error: "not performed: verification was aborted"})
+			return
+		}
+		opts.Hooks.checkStart(name)
+		var check Check
+		if missing := missingFields(data, name); opts.Partial && len(missing) > 0 {
+			check = Check{
+				Name:    name,
+				Skipped: true,
+				Error:   "data not provided: " + strings.Join(missing, ", "),
+			}
+			withheld = true
+		} else {
+			began := time.Now()
+			check = perform()
+			result.Timings.record(name, time.Since(began))
+		}
+		result.addCheck(check)
+		if opts.Hooks.checkComplete(check) {
+			result.Aborted = true
+			result.Passed = false
+		}
+	}
+
+	run(CheckBetAmounts, func() Check {
+		return betAmountsCheck(data, opts.MaxBet)
+	})
+
+	if opts.Game.UniqueGifts {
+		run(CheckUniqueGifts, func() Check {
+			return uniqueGiftsCheck(data)
+		})
+	}
+
+	run(CheckServerHash, func() Check {
+		expectedHash := opts.Game.commitHash(data.ServerSeed)
+		committed, field := data.ServerHash, "server_hash"
+		// A seed committed as part of a batch is checked against the
+		// batch's Merkle root through its inclusion proof
+		var proofErr error
+		leaf, leafMatches := expectedHash, true
+		if data.SeedRoot != "" {
+			leafMatches = data.ServerHash == "" || opts.HexMatches(leaf, data.ServerHash)
+			committed, field = data.SeedRoot, "seed_root"
+			expectedHash, proofErr = seedMerkleRoot(opts.Game, expectedHash, data.SeedIndex, data.SeedProof)
+		}
+		if opts.Commitment != nil {
+			result.Commitment = opts.Commitment
+			declared := committed
+			committed = opts.Commitment.Hash
+			if declared != "" && !opts.sameHex(declared, committed) {
+				result.Alerts = append(result.Alerts, "The round's "+field+" "+ShortHash(declared)+
+					" differs from the commitment published at "+opts.Commitment.Source+" — the round does not report the hash that was committed to.")
+			}
+		}
+		check := Check{
+			Name:     CheckServerHash,
+			Passed:   opts.HexMatches(expectedHash, committed),
+			Expected: expectedHash,
+			Actual:   committed,
+		}
+		// An empty seed hashes like any other string, so a round revealing
+		// none must not pass against the digest of "". A backend that
+		// publishes the raw seed, or anything else that cannot be a digest,
+		// is misconfigured rather than merely wrong.
+		if data.ServerSeed == "" {
+			check.Passed = false
+			check.Error = "No server seed revealed: there is nothing to check against the commitment"
+		} else if committed == data.ServerSeed {
+			check.Error = "Server hash appears not to be a hash: it is identical to the server seed"
+		} else if err := ValidateCommitment(opts.Game, committed); err != nil {
+			check.Error = "Server hash appears not to be a hash: " + err.Error()
+		} else if proofErr != nil {
+			check.Passed = false
+			check.Error = "Invalid seed inclusion proof: " + proofErr.Error()
+		} else if !leafMatches {
+			check.Passed = false
+			check.Expected, check.Actual = leaf, data.ServerHash
+			check.Error = "server_hash is not the hash of the revealed server seed, so it is not the seed's leaf in the committed batch"
+		}
+		return check
+	})
+
+	run(CheckClientSeed, func() Check {
+		if data.isSharded() {
+			check := shardedClientSeedCheck(data, opts, result.Trace)
+			result.ComputedClientSeed = check.Expected
+			return check
+		}
+		result.ComputedClientSeed = RoundClientSeed(opts.Game, data, result.Trace)
+		return Check{
+			Name:     CheckClientSeed,
+			Passed:   opts.HexMatches(result.ComputedClientSeed, data.ClientSeed),
+			Expected: result.ComputedClientSeed,
+			Actual:   data.ClientSeed,
+		}
+	})
+
+	run(CheckResult, func() Check {
+		result.ComputedResult = CalculateResultTrace(opts.Game, data.ServerSeed, data.ClientSeed, data.RoundNumber, data.PreviousHash, result.Trace)
+		if opts.ShowBigInt {
+			d := DeriveResult(opts.Game, data.ServerSeed, opts.Game.Message(data.ServerSeed, data.ClientSeed, data.RoundNumber, data.PreviousHash))
+			result.Derivation = &d
+		}
+		computed, claimed := opts.Game.FormatResult(result.ComputedResult), opts.Game.FormatResult(data.Result)
+		return Check{
+			Name:     CheckResult,
+			Passed:   computed == claimed,
+			Expected: computed,
+			Actual:   claimed,
+		}
+	})
+
+	run(CheckWinner, func() Check {
+		check := Check{Name: CheckWinner, Actual: data.WinnerAddress}
+		if data.Cancelled {
+			// Nobody is selected in a refunded round; any winner is bogus
+			check.Passed = data.WinnerAddress == ""
+			return check
+		}
+		// With a single bet the winner does not depend on the result at all
+		singleBet := len(data.WinningBets()) == 1 && !data.HasHiddenShare()
+		domainErr := CheckResultDomain(data.Result)
+		if singleBet {
+			domainErr = nil
+		}
+
+		winner, err := SelectRoundWinner(opts.Game, data, data.Result)
+		if err != nil {
+			var noRange *NoRangeError
+			var rangeMath *rangeMathError
+			var rangeTotal *rangeTotalError
+			var betAmount *betAmountError
+			switch {
+			case domainErr != nil:
+				check.Error = "Result out of domain: " + domainErr.Error()
+			case errors.As(err, &noRange):
+				check.Error = "No winner range: " + err.Error()
+			case errors.As(err, &rangeTotal):
+				check.Error = "Invalid bet amounts: " + err.Error()
+			case errors.As(err, &betAmount):
+				check.Error = "Invalid bet amount: " + err.Error()
+			case errors.As(err, &rangeMath):
+				check.Error = "Invalid bet amount: " + err.Error()
+			case data.AmountsAreShares:
+				check.Error = "Invalid bet shares: " + err.Error()
+			default:
+				check.Error = "Invalid range denominator: " + err.Error()
+			}
+			return check
+		}
+		result.ComputedWinner = winner
+		result.HouseWon = IsHouse(result.HouseAddresses, winner)
+		ranges, _ := data.WinnerRanges()
+		result.WinnerRanges = ranges
+		if opts.Game.TieBreak && len(data.Bets) > 1 {
+			if lower, upper, ok := findBoundaryTie(ranges, data.Result); ok {
+				result.TieBreak = resolveTieBreak(opts.Game, data, lower, upper, result.Trace)
+				result.ComputedWinner = result.TieBreak.Winner
+				result.HouseWon = IsHouse(result.HouseAddresses, result.ComputedWinner)
+			}
+		}
+		if !singleBet && len(data.WinningBets()) > 0 {
+			coverage := ComputeRangeCoverage(ranges, data.HasHiddenShare())
+			result.RangeCoverage = &coverage
+			if !coverage.Complete {
+				result.Alerts = append(result.Alerts, fmt.Sprintf(
+					"Winner ranges cover %.3f%% of the domain rather than exactly 100%% — the bet amounts are inconsistent.", coverage.Total))
+			}
+			if result.TieBreak == nil && opts.Game.WinnerRule != WinnerRuleNearest {
+				result.ClaimedRange = assertClaimedRange(ranges, data.WinnerAddress, data.Result)
+			}
+		}
+		check.Expected = result.ComputedWinner
+		check.Passed = result.ComputedWinner == data.WinnerAddress
+		if !check.Passed && data.MinWinningBet > 0 && data.WinnerAddress != "" && !data.canWin(data.WinnerAddress) {
+			check.Error = fmt.Sprintf("Ineligible winner: %s placed no bet of at least the minimum winning bet %.3f TON",
+				data.WinnerAddress, data.MinWinningBet)
+			if result.ComputedWinner != "" {
+				check.Error += "; the calculated winner is " + result.ComputedWinner
+			}
+		}
+		if domainErr != nil {
+			check.Passed = false
+			check.Error = "Result out of domain: " + domainErr.Error()
+		}
+		return check
+	})
+
+	if data.BeaconValue != "" || data.BeaconRound != 0 {
+		run(CheckBeacon, func() Check {
+			return beaconCheck(data, opts.Beacons)
+		})
+	}
+
+	if data.CrashMultiplier != 0 {
+		run(CheckCrashMultiplier, func() Check {
+			return crashMultiplierCheck(data)
+		})
+	}
+
+	if len(data.ShuffleInput) > 0 || len(data.ShuffleOutput) > 0 {
+		run(CheckShuffle, func() Check {
+			return shuffleCheck(opts.Game, data, result.Trace)
+		})
+	}
+
+	if opts.MinSeedBits > 0 {
+		run(CheckSeedStrength, func() Check {
+			return seedStrengthCheck(data.ServerSeed, opts.MinSeedBits)
+		})
+	}
+
+	if opts.ExpectWinner != "" {
+		run(CheckExpectedWinner, func() Check {
+			return Check{
+				Name:     CheckExpectedWinner,
+				Passed:   result.ComputedWinner == opts.ExpectWinner,
+				Expected: opts.ExpectWinner,
+				Actual:   result.ComputedWinner,
+			}
+		})
+	}
+
+	result.Partial = withheld
+	result.classifyFailures()
+	result.Duration = time.Since(start)
+	if result.Timings != nil {
+		result.Timings.TotalMS = DurationMS(result.Duration)
+		result.Timings.HashOps = countHashOps(data, result)
+	}
+	return result
+}
+
+// alertWinnerSubstitution is raised when the draw is honest but the payout
+// went to someone else
+const alertWinnerSubstitution = "Result is correct but the declared winner does not match the result's range — this indicates deliberate winner substitution."
+
+// alertUpperBoundary is raised when the declared winner's range ends exactly
+// at the result, i.e. the operator treated ranges as (start, end]
+const alertUpperBoundary = "Declared winner's range ends exactly at the result — the operator appears to read ranges as (start, end], but a result on a boundary belongs to the range starting there."
+
+// classifyFailures adds alerts describing the kind of cheating a
+// combination of check outcomes points to
+func (r *Report) classifyFailures() {
+	resultCheck, winnerCheck := r.Check(CheckResult), r.Check(CheckWinner)
+	if r.Cancelled || resultCheck == nil || winnerCheck == nil || resultCheck.Skipped || winnerCheck.Skipped {
+		return
+	}
+	if !resultCheck.Passed || winnerCheck.Passed || winnerCheck.Error != "" {
+		return
+	}
+	if r.ClaimedRange != nil && r.ClaimedRange.UpperBoundary {
+		r.Alerts = append(r.Alerts, alertUpperBoundary)
+	} else {
+		r.Alerts = append(r.Alerts, alertWinnerSubstitution)
+	}
+}
+
+// missingFields lists the round fields a check needs that are empty
+func missingFields(data RoundVerificationData, check string) []string {
+	present := map[string]bool{
+		"server_seed":    data.ServerSeed != "",
+		"server_hash":    data.ServerHash != "",
+		"client_seed":    data.ClientSeed != "",
+		"bets":           len(data.Bets) > 0,
+		"winner_address": data.WinnerAddress != "",
+		"beacon_value":   data.BeaconValue != "",
+		"seed_root":      data.SeedRoot != "",
+	}
+
+	var required []string
+	switch check {
+	case CheckServerHash:
+		required = []string{"server_seed", "server_hash"}
+		if data.SeedRoot != "" {
+			required = []string{"server_seed", "seed_root"}
+		}
+	case CheckClientSeed:
+		required = []string{"bets", "client_seed"}
+		if len(data.ShardRoots) > 0 {
+			required = []string{"client_seed"}
+		}
+	case CheckResult, CheckShuffle:
+		required = []string{"server_seed", "client_seed"}
+	case CheckWinner:
+		if !data.Cancelled {
+			required = []string{"bets", "winner_address"}
+		}
+	case CheckSeedStrength:
+		required = []string{"server_seed"}
+	case CheckBeacon:
+		required = []string{"beacon_value"}
+	case CheckExpectedWinner, CheckBetAmounts, CheckUniqueGifts:
+		required = []string{"bets"}
+	}
+
+	var missing []string
+	for _, field := range required {
+		if !present[field] {
+			missing = append(missing, field)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,204 @@
+package verify
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// seededRound builds a round that is consistent with its seeds: the server
+// hash commits to the seed, the client seed hashes the bets and the result is
+// derived from both. The claimed winner is left to the caller.
+func seededRound(seed int, bets ...VerificationBet) RoundVerificationData {
+	data := RoundVerificationData{
+		Success:     true,
+		RoundID:     fmt.Sprintf("round-%x", seed),
+		RoundNumber: 1,
+		ServerSeed:  fmt.Sprintf("%064x", seed),
+		Bets:        bets,
+	}
+	data.ServerHash = HashString(data.ServerSeed)
+	data.ClientSeed = ComputeClientSeed(bets)
+	data.Result = calculateResult(data.ServerSeed, data.ClientSeed, data.RoundNumber, data.PreviousHash)
+	for _, bet := range bets {
+		data.TotalPot += bet.Amount
+	}
+	return data
+}
+
+func float(v float64) *float64 {
+	return &v
+}
+
+func TestVerifyRoundSingleBet(t *testing.T) {
+	lone := VerificationBet{PlayerAddress: "EQA1aaaaaaaa4B2C", Amount: 2.5, GiftID: "g1"}
+	tests := []struct {
+		name string
+		seed int
+		// result is the value the seed must yield, when the case is
+		// about a particular result
+		result *float64
+	}{
+		{name: "result 0", seed: 0xfe65, result: float(0)},
+		{name: "result 100", seed: 0x2191b, result: float(100)},
+		{name: "seed 1", seed: 1},
+		{name: "seed 2", seed: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := seededRound(tt.seed, lone)
+			if tt.result != nil && data.Result != *tt.result {
+				t.Fatalf("seed %x yields result %.3f, want %.3f", tt.seed, data.Result, *tt.result)
+			}
+			data.WinnerAddress = lone.PlayerAddress
+
+			report, err := VerifyRound(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !report.Passed {
+				t.Fatalf("single-bet round with result %.3f failed: %v", data.Result, report.FailedChecks())
+			}
+			if report.ComputedWinner != lone.PlayerAddress {
+				t.Errorf("computed winner %q, want the lone player", report.ComputedWinner)
+			}
+
+			data.WinnerAddress = "EQB2bbbbbbbb5C3D"
+			if report, _ := VerifyRound(data); report.CheckPassed(CheckWinner) {
+				t.Error("a winner who placed no bet passed the winner check")
+			}
+		})
+	}
+}
+
+// threeBets is a round's bets listed out of address order
+var threeBets = []VerificationBet{
+	{PlayerAddress: "EQC3zzzzzzzz6D4E", Amount: 15, GiftID: "g3"},
+	{PlayerAddress: "EQA1aaaaaaaa4B2C", Amount: 13.75, GiftID: "g1"},
+	{PlayerAddress: "EQB2bbbbbbbb5C3D", Amount: 15.92},
+}
+
+func TestVerifyRoundZeroResult(t *testing.T) {
+	data := seededRound(0x377af, threeBets...)
+	if data.Result != 0 {
+		t.Fatalf("seed yields result %.3f, want 0.000", data.Result)
+	}
+
+	tests := []struct {
+		name   string
+		winner string
+		passed bool
+	}{
+		{name: "first sorted player", winner: "EQA1aaaaaaaa4B2C", passed: true},
+		{name: "first listed player", winner: "EQC3zzzzzzzz6D4E"},
+		{name: "second sorted player", winner: "EQB2bbbbbbbb5C3D"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := data
+			data.WinnerAddress = tt.winner
+			report, err := VerifyRound(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.Passed != tt.passed {
+				t.Errorf("passed = %v, want %v (failed: %v)", report.Passed, tt.passed, report.FailedChecks())
+			}
+			if report.ComputedWinner != "EQA1aaaaaaaa4B2C" {
+				t.Errorf("computed winner %q, want the first sorted player", report.ComputedWinner)
+			}
+		})
+	}
+}
+
+func TestVerifyRoundTopResult(t *testing.T) {
+	data := seededRound(0x26a0c, threeBets...)
+	if data.Result != ResultDomainMax {
+		t.Fatalf("seed yields result %.3f, want 100.000", data.Result)
+	}
+
+	tests := []struct {
+		name   string
+		winner string
+		passed bool
+	}{
+		{name: "last sorted player", winner: "EQC3zzzzzzzz6D4E", passed: true},
+		{name: "first sorted player", winner: "EQA1aaaaaaaa4B2C"},
+		{name: "no winner", winner: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := data
+			data.WinnerAddress = tt.winner
+			report, err := VerifyRound(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.Passed != tt.passed {
+				t.Errorf("passed = %v, want %v (failed: %v)", report.Passed, tt.passed, report.FailedChecks())
+			}
+			if report.ComputedWinner != "EQC3zzzzzzzz6D4E" {
+				t.Errorf("computed winner %q, want the last sorted player", report.ComputedWinner)
+			}
+		})
+	}
+}
+
+func TestServerHashCheck(t *testing.T) {
+	data := seededRound(0x26a0c, threeBets...)
+	data.WinnerAddress = "EQC3zzzzzzzz6D4E"
+	published := &PublishedCommitment{Source: "https://example.com/commit", Hash: data.ServerHash}
+
+	tests := []struct {
+		name       string
+		change     func(d *RoundVerificationData)
+		opts       Options
+		wantPassed bool
+		wantError  string
+		wantAlerts int
+	}{
+		{name: "revealed seed", wantPassed: true},
+		{
+			name:      "no seed against the digest of an empty string",
+			change:    func(d *RoundVerificationData) { d.ServerSeed, d.ServerHash = "", HashString("") },
+			wantError: "No server seed revealed: there is nothing to check against the commitment",
+		},
+		{
+			name:      "no seed and no hash",
+			change:    func(d *RoundVerificationData) { d.ServerSeed, d.ServerHash = "", "" },
+			wantError: "No server seed revealed: there is nothing to check against the commitment",
+		},
+		{
+			name:       "commitment differs only in case",
+			change:     func(d *RoundVerificationData) { d.ServerHash = strings.ToUpper(d.ServerHash) },
+			opts:       Options{Commitment: published},
+			wantPassed: true,
+			wantAlerts: 1,
+		},
+		{
+			name:       "commitment differs only in case, case ignored",
+			change:     func(d *RoundVerificationData) { d.ServerHash = strings.ToUpper(d.ServerHash) },
+			opts:       Options{Commitment: published, HexCaseInsensitive: true},
+			wantPassed: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := data
+			if tt.change != nil {
+				tt.change(&data)
+			}
+			opts := tt.opts
+			opts.Game = defaultGame()
+			report := VerifyRoundWithOptions(data, opts)
+			check := report.Check(CheckServerHash)
+			if check.Passed != tt.wantPassed || check.Error != tt.wantError {
+				t.Errorf("server hash check passed=%v error %q, want %v %q", check.Passed, check.Error, tt.wantPassed, tt.wantError)
+			}
+			if len(report.Alerts) != tt.wantAlerts {
+				t.Errorf("alerts %q, want %d", report.Alerts, tt.wantAlerts)
+			}
+		})
+	}
+}
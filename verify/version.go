@@ -0,0 +1,58 @@
+package verify
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// version may be set at build time with -ldflags
+// "-X github.com/lazyton/jackpot-verification/verify.version=v1.2.3";
+// module builds report their version through the embedded build info instead.
+var version = "dev"
+
+// BuildInfo identifies the verifier build that produced a result
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	Modified  bool   `json:"modified,omitempty"`
+	GoVersion string `json:"go_version"`
+}
+
+func ReadBuildInfo() BuildInfo {
+	info := BuildInfo{Version: version, GoVersion: runtime.Version()}
+
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	if v := bi.Main.Version; v != "" && v != "(devel)" && version == "dev" {
+		info.Version = v
+	}
+	for _, setting := range bi.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.Commit = setting.Value
+		case "vcs.modified":
+			info.Modified = setting.Value == "true"
+		}
+	}
+	return info
+}
+
+// VersionString is the one-line version recorded in machine-readable output
+func VersionString() string {
+	info := ReadBuildInfo()
+	s := info.Version
+	if info.Commit != "" {
+		commit := info.Commit
+		if len(commit) > 12 {
+			commit = commit[:12]
+		}
+		s += " (" + commit
+		if info.Modified {
+			s += "-dirty"
+		}
+		s += ")"
+	}
+	return s + " " + info.GoVersion
+}
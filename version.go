@@ -0,0 +1,20 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/lazyton/jackpot-verification/verify"
+)
+
+func printVersion() {
+	info := verify.ReadBuildInfo()
+	fmt.Printf("verify_jackpot_round %s\n", info.Version)
+	if info.Commit != "" {
+		dirty := ""
+		if info.Modified {
+			dirty = " (modified)"
+		}
+		fmt.Printf("commit: %s%s\n", info.Commit, dirty)
+	}
+	fmt.Printf("go: %s\n", info.GoVersion)
+}
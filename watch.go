@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// watchInterval is how often --watch polls the input file for changes
+const watchInterval = 500 * time.Millisecond
+
+// clearScreen moves the cursor home and clears the terminal
+const clearScreen = "\033[H\033[2J"
+
+// watchFile calls run once, then again whenever the file's modification time
+// or size changes, until ctx is cancelled. Polling keeps the tool free of
+// platform-specific file notification APIs.
+func watchFile(ctx context.Context, path string, run func()) {
+	last, _ := os.Stat(path)
+	run()
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(path)
+		if err != nil || !fileChanged(last, info) {
+			continue
+		}
+		last = info
+		run()
+	}
+}
+
+func fileChanged(before, after os.FileInfo) bool {
+	if before == nil {
+		return true
+	}
+	return !after.ModTime().Equal(before.ModTime()) || after.Size() != before.Size()
+}
+
+// printWatchHeader clears the screen before each re-verification
+func printWatchHeader(path string) {
+	fmt.Print(clearScreen)
+	fmt.Printf("👀 Watching %s (Ctrl-C to stop) — verified at %s\n\n", path, time.Now().Format("15:04:05"))
+}
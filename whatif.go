@@ -0,0 +1,48 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/lazyton/jackpot-verification/verify"
+)
+
+// printWhatIf shows who would have won the round had the result been
+// hypothetical instead of the claimed value. No checks are performed.
+func printWhatIf(game verify.GameConfig, data verify.RoundVerificationData, hypothetical float64) {
+	fmt.Printf("🔮 What-if for Jackpot Round #%d (%s)\n", data.RoundNumber, data.RoundID)
+	fmt.Printf("🎯 Hypothetical Result: %.3f (claimed: %.3f)\n", hypothetical, data.Result)
+	fmt.Println(strings.Repeat("=", 60))
+
+	if err := verify.CheckResultDomain(hypothetical); err != nil {
+		fmt.Printf("    ⚠️  Result out of domain: %v\n", err)
+	}
+
+	winner, err := verify.SelectRoundWinner(game, data, hypothetical)
+	var noRange *verify.NoRangeError
+	if errors.As(err, &noRange) {
+		fmt.Printf("🏆 Would win: nobody — %v\n", err)
+		return
+	}
+	if err != nil {
+		fmt.Printf("    ❌ Cannot compute ranges: %v\n", err)
+		return
+	}
+	if winner == "" {
+		fmt.Println("🏆 Would win: an undisclosed bet")
+	} else if verify.IsHouse(game.HouseAddresses, winner) {
+		fmt.Printf("🏆 Would win: 🏠 the house (%s)\n", winner)
+	} else {
+		fmt.Printf("🏆 Would win: %s\n", winner)
+	}
+	if winner == data.WinnerAddress {
+		fmt.Println("    Same player as the actual winner")
+	} else {
+		fmt.Printf("    Actual winner was %s\n", data.WinnerAddress)
+	}
+
+	fmt.Println("📐 Winner Ranges:")
+	showRoundRanges(os.Stdout, data, hypothetical, game.WinnerRule, game.HouseAddresses)
+}